@@ -126,16 +126,242 @@ func (c *Call) String() string {
 	return fmt.Sprintf("Call{%s(%s)}", c.Callee, argsStr)
 }
 
-func (b *Binary) isExpression()     {}
-func (g *Grouping) isExpression()   {}
-func (l *Literal) isExpression()    {}
-func (u *Unary) isExpression()      {}
-func (l *Let) isExpression()        {}
-func (v *Variable) isExpression()   {}
-func (a *Assignment) isExpression() {}
-func (l *Logical) isExpression()    {}
-func (d *Debug) isExpression()      {}
-func (c *Call) isExpression()       {}
+type Get struct {
+	Object Expression
+	Name   token.Token
+}
+
+func (g *Get) String() string {
+	return fmt.Sprintf("<Get{Object: %s, Name: %s}>", g.Object, g.Name)
+}
+
+type Set struct {
+	Object Expression
+	Name   token.Token
+	Value  Expression
+}
+
+func (s *Set) String() string {
+	return fmt.Sprintf("<Set{Object: %s, Name: %s, Value: %s}>", s.Object, s.Name, s.Value)
+}
+
+type This struct {
+	Keyword token.Token
+}
+
+func (t *This) String() string {
+	return "<This{}>"
+}
+
+type Super struct {
+	Keyword token.Token
+	Method  token.Token
+}
+
+func (s *Super) String() string {
+	return fmt.Sprintf("<Super{Method: %s}>", s.Method)
+}
+
+// Lambda is an anonymous function expression. Its declaration's name
+// is the "fun" keyword that introduced it.
+type Lambda struct {
+	Declaration *FunctionStatement
+}
+
+func (l *Lambda) String() string {
+	var params []string
+	for _, p := range l.Declaration.Params {
+		params = append(params, p.String())
+	}
+
+	paramsStr := strings.Join(params, ", ")
+	return fmt.Sprintf("<Lambda{(%s){...}}>", paramsStr)
+}
+
+type List struct {
+	Bracket  token.Token
+	Elements []Expression
+}
+
+func (l *List) String() string {
+	var elements []string
+	for _, e := range l.Elements {
+		elements = append(elements, e.String())
+	}
+
+	elementsStr := strings.Join(elements, ", ")
+	return fmt.Sprintf("<List{%s}>", elementsStr)
+}
+
+type Index struct {
+	Object  Expression
+	Bracket token.Token
+	Index   Expression
+}
+
+func (i *Index) String() string {
+	return fmt.Sprintf("<Index{Object: %s, Index: %s}>", i.Object, i.Index)
+}
+
+type IndexSet struct {
+	Object  Expression
+	Bracket token.Token
+	Index   Expression
+	Value   Expression
+}
+
+func (i *IndexSet) String() string {
+	return fmt.Sprintf("<IndexSet{Object: %s, Index: %s, Value: %s}>", i.Object, i.Index, i.Value)
+}
+
+// Map is a map literal. Keys[i] is the key for Values[i].
+type Map struct {
+	Brace  token.Token
+	Keys   []Expression
+	Values []Expression
+}
+
+func (m *Map) String() string {
+	var entries []string
+	for i := range m.Keys {
+		entries = append(entries, fmt.Sprintf("%s: %s", m.Keys[i], m.Values[i]))
+	}
+
+	entriesStr := strings.Join(entries, ", ")
+	return fmt.Sprintf("<Map{%s}>", entriesStr)
+}
+
+// Stringify converts the value of its expression to a string, the
+// same way that printing it would. It's used to build interpolated
+// strings.
+type Stringify struct {
+	Expression Expression
+}
+
+func (s *Stringify) String() string {
+	return fmt.Sprintf("<Stringify{%s}>", s.Expression)
+}
+
+// CompoundAssignment is an assignment like "a += b". Target is the
+// Variable, Get or Index expression that's being assigned to.
+type CompoundAssignment struct {
+	Target   Expression
+	Operator token.Token
+	Value    Expression
+}
+
+func (c *CompoundAssignment) String() string {
+	return fmt.Sprintf("<CompoundAssignment{Target: %s, Operator: %s, Value: %s}>", c.Target, c.Operator, c.Value)
+}
+
+// Update is an increment or decrement ("++" or "--") of its target, which
+// is a Variable, Get or Index expression. Prefix updates evaluate to the new
+// value, and postfix ones to the old value.
+type Update struct {
+	Target   Expression
+	Operator token.Token
+	Prefix   bool
+}
+
+func (u *Update) String() string {
+	return fmt.Sprintf("<Update{Target: %s, Operator: %s, Prefix: %t}>", u.Target, u.Operator, u.Prefix)
+}
+
+// Conditional is a ternary "cond ? then : else" expression.
+type Conditional struct {
+	Condition Expression
+	Question  token.Token
+	Then      Expression
+	Else      Expression
+}
+
+func (c *Conditional) String() string {
+	return fmt.Sprintf("<Conditional{Condition: %s, Then: %s, Else: %s}>", c.Condition, c.Then, c.Else)
+}
+
+// Spread is an argument that's a list, whose elements are passed as
+// separate arguments. It can only appear in a call's arguments.
+type Spread struct {
+	Ellipsis token.Token
+	Value    Expression
+}
+
+func (s *Spread) String() string {
+	return fmt.Sprintf("<Spread{%s}>", s.Value)
+}
+
+// Range is a range of numbers from Start up to End, which includes End if
+// Operator is "..=" rather than "..".
+type Range struct {
+	Start    Expression
+	Operator token.Token
+	End      Expression
+}
+
+func (r *Range) String() string {
+	return fmt.Sprintf("<Range{%s %s %s}>", r.Start, r.Operator.Lexeme, r.End)
+}
+
+// Tuple is the list of values in a "return a, b;" statement. It
+// evaluates to an immutable tuple of those values.
+type Tuple struct {
+	Elements []Expression
+}
+
+func (t *Tuple) String() string {
+	var elements []string
+	for _, e := range t.Elements {
+		elements = append(elements, e.String())
+	}
+
+	return fmt.Sprintf("<Tuple{%s}>", strings.Join(elements, ", "))
+}
+
+// Destructure assigns each element of a list or tuple to the matching
+// target, as in "[a, b] = [b, a]". Each target is a Variable, Get or
+// Index expression.
+type Destructure struct {
+	Bracket token.Token
+	Targets []Expression
+	Value   Expression
+}
+
+func (d *Destructure) String() string {
+	var targets []string
+	for _, t := range d.Targets {
+		targets = append(targets, t.String())
+	}
+
+	return fmt.Sprintf("<Destructure{[%s] = %s}>", strings.Join(targets, ", "), d.Value)
+}
+
+func (b *Binary) isExpression()             {}
+func (g *Grouping) isExpression()           {}
+func (l *Literal) isExpression()            {}
+func (u *Unary) isExpression()              {}
+func (l *Let) isExpression()                {}
+func (v *Variable) isExpression()           {}
+func (a *Assignment) isExpression()         {}
+func (l *Logical) isExpression()            {}
+func (d *Debug) isExpression()              {}
+func (c *Call) isExpression()               {}
+func (g *Get) isExpression()                {}
+func (s *Set) isExpression()                {}
+func (t *This) isExpression()               {}
+func (s *Super) isExpression()              {}
+func (l *Lambda) isExpression()             {}
+func (l *List) isExpression()               {}
+func (i *Index) isExpression()              {}
+func (i *IndexSet) isExpression()           {}
+func (m *Map) isExpression()                {}
+func (s *Stringify) isExpression()          {}
+func (c *CompoundAssignment) isExpression() {}
+func (u *Update) isExpression()             {}
+func (c *Conditional) isExpression()        {}
+func (s *Spread) isExpression()             {}
+func (t *Tuple) isExpression()              {}
+func (r *Range) isExpression()              {}
+func (d *Destructure) isExpression()        {}
 
 var (
 	_ Expression = &Binary{}
@@ -148,4 +374,21 @@ var (
 	_ Expression = &Logical{}
 	_ Expression = &Debug{}
 	_ Expression = &Call{}
+	_ Expression = &Get{}
+	_ Expression = &Set{}
+	_ Expression = &This{}
+	_ Expression = &Super{}
+	_ Expression = &Lambda{}
+	_ Expression = &List{}
+	_ Expression = &Index{}
+	_ Expression = &IndexSet{}
+	_ Expression = &Map{}
+	_ Expression = &Stringify{}
+	_ Expression = &CompoundAssignment{}
+	_ Expression = &Update{}
+	_ Expression = &Conditional{}
+	_ Expression = &Spread{}
+	_ Expression = &Tuple{}
+	_ Expression = &Destructure{}
+	_ Expression = &Range{}
 )
@@ -31,10 +31,43 @@ func (e *ExpressionStatement) String() string {
 type VarStatement struct {
 	Name        token.Token
 	Initializer Expression
+	Doc         string
+	// Const is true if the variable was declared with "const", which
+	// means that it can't be assigned to after it's initialized.
+	Const bool
 }
 
 func (v *VarStatement) String() string {
-	return fmt.Sprintf("<VarStatement{%s = %s}>", v.Name, v.Initializer)
+	kind := "VarStatement"
+	if v.Const {
+		kind = "ConstStatement"
+	}
+
+	return fmt.Sprintf("<%s{%s = %s}>", kind, v.Name, v.Initializer)
+}
+
+// DestructureStatement declares a variable for each element of a list or
+// tuple, as in "var [x, y] = pair();".
+type DestructureStatement struct {
+	Bracket     token.Token
+	Names       []token.Token
+	Initializer Expression
+	Doc         string
+	Const       bool
+}
+
+func (d *DestructureStatement) String() string {
+	kind := "VarStatement"
+	if d.Const {
+		kind = "ConstStatement"
+	}
+
+	var names []string
+	for _, n := range d.Names {
+		names = append(names, n.String())
+	}
+
+	return fmt.Sprintf("<%s{[%s] = %s}>", kind, strings.Join(names, ", "), d.Initializer)
 }
 
 type BlockStatement struct {
@@ -67,25 +100,97 @@ func (i *IfStatement) String() string {
 	return fmt.Sprintf("<IfStatement{Condition:%s, Then:%s, Else:%s}>", i.Condition, i.ThenBranch, elseStr)
 }
 
+// WhileStatement is used for both while and (desugared) for loops.
+// Increment is the for loop's increment clause, if it had one: it's
+// run after every iteration of the body, including ones that were cut
+// short by a "continue".
 type WhileStatement struct {
+	Label     *token.Token
 	Condition Expression
 	Body      Statement
+	Increment Expression
 }
 
 func (w *WhileStatement) String() string {
-	return fmt.Sprintf("<WhileStatement{Condition:%s, Body: %s}>", w.Condition, w.Body)
+	var labelStr string
+	if w.Label != nil {
+		labelStr = w.Label.Lexeme + ": "
+	}
+
+	return fmt.Sprintf("<WhileStatement{%sCondition:%s, Body: %s, Increment: %s}>", labelStr, w.Condition, w.Body, w.Increment)
+}
+
+// ForInStatement runs Body once for each value produced by Iterable, with
+// Name bound to the value in a new scope for each iteration.
+type ForInStatement struct {
+	Label    *token.Token
+	Name     token.Token
+	In       token.Token
+	Iterable Expression
+	Body     Statement
+}
+
+func (f *ForInStatement) String() string {
+	var labelStr string
+	if f.Label != nil {
+		labelStr = f.Label.Lexeme + ": "
+	}
+
+	return fmt.Sprintf("<ForInStatement{%s%s in %s, Body: %s}>", labelStr, f.Name.Lexeme, f.Iterable, f.Body)
+}
+
+type BreakStatement struct {
+	Keyword token.Token
+	Label   *token.Token
+}
+
+func (b *BreakStatement) String() string {
+	if b.Label != nil {
+		return fmt.Sprintf("<Break{%s}>", b.Label.Lexeme)
+	}
+
+	return "<Break{}>"
+}
+
+type ContinueStatement struct {
+	Keyword token.Token
+	Label   *token.Token
+}
+
+func (c *ContinueStatement) String() string {
+	if c.Label != nil {
+		return fmt.Sprintf("<Continue{%s}>", c.Label.Lexeme)
+	}
+
+	return "<Continue{}>"
 }
 
 type FunctionStatement struct {
 	Name   token.Token
 	Params []token.Token
-	Body   []Statement
+	// Defaults holds the default value of each parameter in Params, or
+	// nil for parameters that don't have one.
+	Defaults []Expression
+	// Rest is the parameter that collects any extra arguments into a
+	// list, or nil if the function doesn't have one.
+	Rest *token.Token
+	Body []Statement
+	Doc  string
 }
 
 func (f *FunctionStatement) String() string {
 	var params []string
-	for _, p := range f.Params {
-		params = append(params, p.String())
+	for i, p := range f.Params {
+		param := p.String()
+		if i < len(f.Defaults) && f.Defaults[i] != nil {
+			param = fmt.Sprintf("%s = %s", param, f.Defaults[i])
+		}
+
+		params = append(params, param)
+	}
+
+	if f.Rest != nil {
+		params = append(params, "..."+f.Rest.String())
 	}
 
 	paramsStr := strings.Join(params, ", ")
@@ -101,22 +206,174 @@ func (r *ReturnStatement) String() string {
 	return fmt.Sprintf("<Return{%s}>", r.Value)
 }
 
-func (p *PrintStatement) IsStatement()      {}
-func (e *ExpressionStatement) IsStatement() {}
-func (v *VarStatement) IsStatement()        {}
-func (b *BlockStatement) IsStatement()      {}
-func (i *IfStatement) IsStatement()         {}
-func (w *WhileStatement) IsStatement()      {}
-func (f *FunctionStatement) IsStatement()   {}
-func (r *ReturnStatement) IsStatement()     {}
+type ClassStatement struct {
+	Name       token.Token
+	Superclass *Variable
+	Methods    []*FunctionStatement
+	Doc        string
+}
+
+func (c *ClassStatement) String() string {
+	var methods []string
+	for _, m := range c.Methods {
+		methods = append(methods, m.String())
+	}
+
+	var superclassStr string
+	if c.Superclass != nil {
+		superclassStr = fmt.Sprintf(" < %s", c.Superclass)
+	}
+
+	methodsStr := strings.Join(methods, ", ")
+	return fmt.Sprintf("<Class{%s%s{%s}}>", c.Name.String(), superclassStr, methodsStr)
+}
+
+type MatchStatement struct {
+	Keyword token.Token
+	Subject Expression
+	Arms    []*MatchArm
+	// Else is run if none of the arms match. It's nil if the match
+	// statement doesn't have an else arm.
+	Else Statement
+}
+
+func (m *MatchStatement) String() string {
+	var arms []string
+	for _, a := range m.Arms {
+		arms = append(arms, a.String())
+	}
+
+	if m.Else != nil {
+		arms = append(arms, fmt.Sprintf("<Else{%s}>", m.Else))
+	}
+
+	armsStr := strings.Join(arms, ", ")
+	return fmt.Sprintf("<Match{Subject: %s, Arms: %s}>", m.Subject, armsStr)
+}
+
+// MatchArm is a single "case" inside of a match statement. It matches if
+// any of its patterns match and its guard (if it has one) is truthy.
+type MatchArm struct {
+	Keyword  token.Token
+	Patterns []*MatchPattern
+	Guard    Expression
+	Body     Statement
+}
+
+func (m *MatchArm) String() string {
+	var patterns []string
+	for _, p := range m.Patterns {
+		patterns = append(patterns, p.String())
+	}
+
+	patternsStr := strings.Join(patterns, ", ")
+	return fmt.Sprintf("<Case{Patterns: %s, Guard: %s, Body: %s}>", patternsStr, m.Guard, m.Body)
+}
+
+// MatchPattern is either a literal pattern that matches values equal to
+// Value, or (if High is set) a range pattern that matches numbers from
+// Value up to High. Ranges exclude High unless Inclusive is set.
+type MatchPattern struct {
+	Token     token.Token
+	Value     *Literal
+	High      *Literal
+	Inclusive bool
+}
+
+func (m *MatchPattern) String() string {
+	if m.High == nil {
+		return m.Value.Output()
+	}
+
+	operator := ".."
+	if m.Inclusive {
+		operator = "..="
+	}
+
+	return fmt.Sprintf("%s%s%s", m.Value.Output(), operator, m.High.Output())
+}
+
+type ThrowStatement struct {
+	Keyword token.Token
+	Value   Expression
+}
+
+func (t *ThrowStatement) String() string {
+	return fmt.Sprintf("<Throw{%s}>", t.Value)
+}
+
+// TryStatement runs Body, and runs Catch with the thrown value bound to
+// CatchName if Body throws. Finally always runs last. Either Catch or
+// Finally can be nil, but not both.
+type TryStatement struct {
+	Keyword   token.Token
+	Body      *BlockStatement
+	CatchName *token.Token
+	Catch     *BlockStatement
+	Finally   *BlockStatement
+}
+
+func (t *TryStatement) String() string {
+	var catchStr string
+	if t.Catch != nil {
+		catchStr = fmt.Sprintf(", Catch(%s): %s", t.CatchName.Lexeme, t.Catch)
+	}
+
+	var finallyStr string
+	if t.Finally != nil {
+		finallyStr = fmt.Sprintf(", Finally: %s", t.Finally)
+	}
+
+	return fmt.Sprintf("<Try{%s%s%s}>", t.Body, catchStr, finallyStr)
+}
+
+// ImportStatement runs the file at Path (a string token) as a module,
+// and binds its namespace to Name. If the import doesn't say "as", Name
+// is derived from the file name.
+type ImportStatement struct {
+	Keyword token.Token
+	Path    token.Token
+	Name    token.Token
+}
+
+func (i *ImportStatement) String() string {
+	return fmt.Sprintf("<Import{%s as %s}>", i.Path.Lexeme, i.Name.Lexeme)
+}
+
+func (p *PrintStatement) IsStatement()       {}
+func (e *ExpressionStatement) IsStatement()  {}
+func (v *VarStatement) IsStatement()         {}
+func (d *DestructureStatement) IsStatement() {}
+func (b *BlockStatement) IsStatement()       {}
+func (i *IfStatement) IsStatement()          {}
+func (w *WhileStatement) IsStatement()       {}
+func (f *ForInStatement) IsStatement()       {}
+func (f *FunctionStatement) IsStatement()    {}
+func (r *ReturnStatement) IsStatement()      {}
+func (c *ClassStatement) IsStatement()       {}
+func (b *BreakStatement) IsStatement()       {}
+func (c *ContinueStatement) IsStatement()    {}
+func (m *MatchStatement) IsStatement()       {}
+func (t *ThrowStatement) IsStatement()       {}
+func (t *TryStatement) IsStatement()         {}
+func (i *ImportStatement) IsStatement()      {}
 
 var (
 	_ Statement = &PrintStatement{}
 	_ Statement = &ExpressionStatement{}
 	_ Statement = &VarStatement{}
+	_ Statement = &DestructureStatement{}
 	_ Statement = &BlockStatement{}
 	_ Statement = &IfStatement{}
 	_ Statement = &WhileStatement{}
+	_ Statement = &ForInStatement{}
 	_ Statement = &FunctionStatement{}
 	_ Statement = &ReturnStatement{}
+	_ Statement = &ClassStatement{}
+	_ Statement = &BreakStatement{}
+	_ Statement = &ContinueStatement{}
+	_ Statement = &MatchStatement{}
+	_ Statement = &ThrowStatement{}
+	_ Statement = &TryStatement{}
+	_ Statement = &ImportStatement{}
 )
@@ -9,8 +9,9 @@ import (
 )
 
 type Environment struct {
-	Parent  *Environment
-	storage map[string]ast.Expression
+	Parent    *Environment
+	storage   map[string]ast.Expression
+	constants map[string]bool
 }
 
 func New(outer *Environment) *Environment {
@@ -62,11 +63,42 @@ func (e *Environment) ancestor(distance int) *Environment {
 	return current
 }
 
+// Root returns the outermost scope of the environment.
+func (e *Environment) Root() *Environment {
+	current := e
+	for current.Parent != nil {
+		current = current.Parent
+	}
+
+	return current
+}
+
 // Define sets the value of "name" to "value" within the current scope.
+// If "name" is already a constant in this scope, it stays one, so callers
+// should check IsConstant before redefining a variable.
 func (e *Environment) Define(name string, value ast.Expression) {
 	e.storage[name] = value
 }
 
+// DefineConstant is like Define, but also marks "name" as a constant
+// within the current scope. It's up to the caller to check IsConstant
+// before assigning to a variable.
+func (e *Environment) DefineConstant(name string, value ast.Expression) {
+	e.storage[name] = value
+
+	if e.constants == nil {
+		e.constants = make(map[string]bool)
+	}
+
+	e.constants[name] = true
+}
+
+// IsConstant reports whether "name" was defined as a constant within the
+// current scope.
+func (e *Environment) IsConstant(name string) bool {
+	return e.constants[name]
+}
+
 // Set sets the value of the variable "name" to "value" within the closest
 // scope that defines "name". The return value is true if the value was set
 // within that scope, or false if no enclosign scope had the variable defined.
@@ -0,0 +1,98 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+	"github.com/ggilmore/bradfield-languages/glox/token"
+)
+
+type LoxClass struct {
+	Name       string
+	Superclass *LoxClass
+	Methods    map[string]*LoxFunction
+}
+
+// FindMethod looks up the method "name" on the class, falling back
+// to the superclass chain if the class doesn't define it itself.
+func (c *LoxClass) FindMethod(name string) (*LoxFunction, bool) {
+	for current := c; current != nil; current = current.Superclass {
+		method, found := current.Methods[name]
+		if found {
+			return method, true
+		}
+	}
+
+	return nil, false
+}
+
+func (c *LoxClass) MinArity() int {
+	initializer, found := c.FindMethod("init")
+	if !found {
+		return 0
+	}
+
+	return initializer.MinArity()
+}
+
+func (c *LoxClass) MaxArity() int {
+	initializer, found := c.FindMethod("init")
+	if !found {
+		return 0
+	}
+
+	return initializer.MaxArity()
+}
+
+func (c *LoxClass) Call(interpreter *Interpreter, arguments []ast.Expression) (ast.Expression, error) {
+	instance := &LoxInstance{
+		Class:  c,
+		fields: make(map[string]*ast.Literal),
+	}
+
+	initializer, found := c.FindMethod("init")
+	if found {
+		_, err := initializer.Bind(instance).Call(interpreter, arguments)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ast.Literal{Value: instance}, nil
+}
+
+func (c *LoxClass) String() string {
+	return c.Name
+}
+
+type LoxInstance struct {
+	Class  *LoxClass
+	fields map[string]*ast.Literal
+}
+
+// Get returns the value of the property "name" on the instance. Fields
+// shadow methods, and methods are bound to the instance before they're
+// returned.
+func (l *LoxInstance) Get(name token.Token) (*ast.Literal, error) {
+	value, found := l.fields[name.Lexeme]
+	if found {
+		return value, nil
+	}
+
+	method, found := l.Class.FindMethod(name.Lexeme)
+	if found {
+		return &ast.Literal{Value: method.Bind(l)}, nil
+	}
+
+	return nil, &Error{name, fmt.Sprintf("Undefined property %q.", name.Lexeme)}
+}
+
+func (l *LoxInstance) Set(name token.Token, value *ast.Literal) {
+	l.fields[name.Lexeme] = value
+}
+
+func (l *LoxInstance) String() string {
+	return fmt.Sprintf("%s instance", l.Class.Name)
+}
+
+var _ LoxCallable = &LoxClass{}
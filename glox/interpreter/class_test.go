@@ -0,0 +1,110 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClasses(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+class Counter {
+  init(start) { this.count = start; }
+  increment() { this.count = this.count + 1; return this; }
+  get() { return this.count; }
+}
+
+var c = Counter(1);
+record(c.increment().increment().get());
+
+// methods stay bound to their instance
+var get = c.get;
+c.count = 10;
+record(get());
+
+// fields shadow methods
+c.get = fun() { return "field"; };
+record(c.get());
+
+// calling init directly returns the instance
+record(c.init(5) == c);
+record(c.count);
+
+record(str(Counter));
+record(str(Counter(0)));
+
+try { c.missing; } catch (e) { record(e.message); }
+try { Counter(); } catch (e) { record(e.message); }
+try { var n = 1; n.field = 2; } catch (e) { record(e.message); }
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	expected := []interface{}{
+		3.0,
+		10.0,
+		"field",
+		true,
+		5.0,
+		"Counter",
+		"Counter instance",
+		`Undefined property "missing".`,
+		"Expected 1 arguments but got 0.",
+		"Only instances have fields.",
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestInheritance(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+class A {
+  init(name) { this.name = name; }
+  greet() { return "A " + this.name; }
+  shout() { return this.greet() + "!"; }
+}
+
+class B < A {
+  greet() { return "B " + super.greet(); }
+}
+
+class C < B {
+  init() { super.init("c"); }
+}
+
+record(B("b").greet());
+record(C().shout());
+
+// super is resolved statically, so it's always B's superclass even when
+// the method runs on an instance of C
+record(C().greet());
+
+var NotAClass = "A";
+try {
+  class D < NotAClass {}
+} catch (e) {
+  record(e.message);
+}
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	expected := []interface{}{
+		"B A b",
+		"B A c!",
+		"B A c",
+		"Superclass must be a class.",
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
@@ -0,0 +1,153 @@
+package interpreter
+
+import (
+	"math"
+	"time"
+
+	// embed the timezone database, so that inZone works the same way
+	// everywhere
+	_ "time/tzdata"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+)
+
+// LoxDate is an instant in time, along with the timezone that it's
+// displayed in.
+type LoxDate struct {
+	time time.Time
+}
+
+func (d *LoxDate) String() string {
+	return d.time.Format(time.RFC3339Nano)
+}
+
+// Clock sets the function that the interpreter gets the current time
+// from, which is time.Now by default. It's meant for tests.
+func Clock(now func() time.Time) Option {
+	return func(i *Interpreter) {
+		i.now = now
+	}
+}
+
+// clock returns the number of seconds since the unix epoch, with
+// sub-second precision.
+func clock(i *Interpreter, _ []*ast.Literal) (interface{}, error) {
+	return float64(i.now().UnixNano()) / float64(time.Second), nil
+}
+
+// timer returns the number of seconds since the interpreter started. It
+// uses the monotonic clock, so unlike clock it's suitable for measuring
+// how long things take.
+func timer(i *Interpreter, _ []*ast.Literal) (interface{}, error) {
+	return i.now().Sub(i.started).Seconds(), nil
+}
+
+// dateNatives are the members of the builtin "date" module. Layouts use
+// the same reference time as go's time package, and durations are
+// numbers of seconds.
+var dateNatives = []*Native{
+	{Name: "now", Fn: dateNow},
+	{Name: "format", Params: []Type{TypeDate, TypeString}, Fn: dateFormat},
+	{Name: "parse", Params: []Type{TypeString, TypeString}, Fn: dateParse},
+	{Name: "add", Params: []Type{TypeDate, TypeNumber}, Fn: dateAdd},
+	{Name: "diff", Params: []Type{TypeDate, TypeDate}, Fn: dateDiff},
+	{Name: "inZone", Params: []Type{TypeDate, TypeString}, Fn: dateInZone},
+	{Name: "unix", Params: []Type{TypeDate}, Fn: dateUnix},
+	{Name: "fromUnix", Params: []Type{TypeNumber}, Fn: dateFromUnix},
+}
+
+var dateLayouts = map[string]string{
+	"rfc3339":  time.RFC3339,
+	"dateOnly": "2006-01-02",
+	"timeOnly": "15:04:05",
+	"dateTime": "2006-01-02 15:04:05",
+}
+
+func dateModule() *LoxModule {
+	module := newNativeModule("date", dateNatives)
+	for name, layout := range dateLayouts {
+		module.globals.Define(name, &ast.Literal{Value: layout})
+	}
+
+	return module
+}
+
+func dateNow(i *Interpreter, _ []*ast.Literal) (interface{}, error) {
+	return &LoxDate{i.now()}, nil
+}
+
+func dateFormat(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	return arguments[0].Value.(*LoxDate).time.Format(arguments[1].Value.(string)), nil
+}
+
+// dateParse parses the first argument using the layout in the second.
+// Dates without a timezone are assumed to be in UTC.
+func dateParse(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	t, err := time.Parse(arguments[1].Value.(string), arguments[0].Value.(string))
+	if err != nil {
+		return nil, nativeErrorf("parse: %s.", err)
+	}
+
+	return &LoxDate{t}, nil
+}
+
+// dateAdd returns the date a number of seconds (which may be negative or
+// fractional) after the given one.
+func dateAdd(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	d := arguments[0].Value.(*LoxDate)
+
+	duration, err := seconds("add", arguments[1].Value.(float64))
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoxDate{d.time.Add(duration)}, nil
+}
+
+// dateDiff returns the number of seconds from the second date to the
+// first.
+func dateDiff(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	a := arguments[0].Value.(*LoxDate)
+	b := arguments[1].Value.(*LoxDate)
+
+	return a.time.Sub(b.time).Seconds(), nil
+}
+
+// dateInZone returns the same instant as the given date, displayed in
+// the named IANA timezone (e.g. "Europe/Paris", or "Local").
+func dateInZone(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	d := arguments[0].Value.(*LoxDate)
+
+	location, err := time.LoadLocation(arguments[1].Value.(string))
+	if err != nil {
+		return nil, nativeErrorf("inZone: unknown timezone %q.", arguments[1].Value)
+	}
+
+	return &LoxDate{d.time.In(location)}, nil
+}
+
+// dateUnix returns the number of seconds since the unix epoch.
+func dateUnix(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	t := arguments[0].Value.(*LoxDate).time
+	return float64(t.UnixNano()) / float64(time.Second), nil
+}
+
+// dateFromUnix returns the UTC date a number of seconds after the unix
+// epoch.
+func dateFromUnix(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	duration, err := seconds("fromUnix", arguments[0].Value.(float64))
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoxDate{time.Unix(0, 0).UTC().Add(duration)}, nil
+}
+
+// seconds converts a number of seconds to a time.Duration.
+func seconds(native string, n float64) (time.Duration, error) {
+	if math.IsNaN(n) || math.Abs(n) > math.MaxInt64/float64(time.Second) {
+		return 0, nativeErrorf("%s: %s seconds is out of range.", native, repr(n))
+	}
+
+	return time.Duration(n * float64(time.Second)), nil
+}
@@ -0,0 +1,44 @@
+package interpreter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClock(t *testing.T) {
+	// every call to the clock advances it by a second, starting
+	// from noon when the interpreter is created
+	now := time.Date(2021, time.June, 1, 11, 59, 59, 0, time.UTC)
+	tick := func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	record, recorded := recorder()
+
+	err := run(t, `
+record(timer());
+record(clock());
+var today = date.now();
+record(date.format(today, date.rfc3339));
+record(date.format(date.inZone(date.add(today, 60), "Asia/Tokyo"), date.dateTime));
+record(date.diff(date.now(), today));
+`, Natives(record), Clock(tick))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	expected := []interface{}{
+		1.0,
+		1622548802.0,
+		"2021-06-01T12:00:03Z",
+		"2021-06-01 21:01:03",
+		1.0,
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
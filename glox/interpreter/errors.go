@@ -38,6 +38,40 @@ func (e *Error) IsLoxLanguageError() {}
 
 var _ errutil.LoxLanguageError = &Error{}
 
+// Halt is implemented by errors that stop a script altogether, such as
+// the one returned by the system package's exit native. Try statements
+// don't catch them, and Interpret returns them unchanged.
+type Halt interface {
+	IsHalt()
+	error
+}
+
+// Warning is a problem that the resolver noticed, but that doesn't stop
+// the program from running.
+type Warning struct {
+	Token   token.Token
+	Message string
+}
+
+func (w *Warning) String() string {
+	return fmt.Sprintf("[line %d] warning: %s", w.Token.Line, w.Message)
+}
+
+// nativeError is returned by native functions, which don't know where
+// in the script they were called from. Interpreter.call reports it as an
+// Error at the location of the call.
+type nativeError struct {
+	Message string
+}
+
+func (e *nativeError) Error() string {
+	return e.Message
+}
+
+func nativeErrorf(format string, args ...interface{}) error {
+	return &nativeError{fmt.Sprintf(format, args...)}
+}
+
 type returnValue interface {
 	ReturnValue() ast.Expression
 }
@@ -55,3 +89,33 @@ func (e *returnError) ReturnValue() ast.Expression {
 }
 
 var _ returnValue = &returnError{}
+
+// breakError and continueError unwind the interpreter out of a loop's
+// body. Label is empty if the statement targets the innermost loop.
+type breakError struct {
+	Label string
+}
+
+func (e *breakError) Error() string {
+	return "This is a break statement."
+}
+
+type continueError struct {
+	Label string
+}
+
+func (e *continueError) Error() string {
+	return "This is a continue statement."
+}
+
+// thrownError unwinds the interpreter out of a throw statement until
+// it's caught by a try statement. Interpret reports exceptions that are
+// never caught as an Error at Token, the throw statement's keyword.
+type thrownError struct {
+	Token token.Token
+	Value *ast.Literal
+}
+
+func (e *thrownError) Error() string {
+	return "This is a throw statement."
+}
@@ -0,0 +1,117 @@
+package interpreter
+
+import (
+	"errors"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+	"github.com/ggilmore/bradfield-languages/glox/token"
+)
+
+// newErrorClass returns Error, the class of the values that runtime
+// errors are caught as. Its instances have "message" and "line" fields.
+// Scripts can throw its instances (or instances of its subclasses)
+// themselves, and the throw statement fills in "line" if it's nil, but
+// any value can be thrown.
+//
+// Error's only method is the equivalent of:
+//
+//	init(message) {
+//	  this.message = message;
+//	  this.line = nil;
+//	}
+//
+// Its body is built directly, so its variables are registered with the
+// distances that the resolver would have given them: parameters are in
+// the function's own scope, and "this" is in the scope around it.
+func (i *Interpreter) newErrorClass() *LoxClass {
+	message := token.Token{Kind: token.KindIdentifier, Lexeme: "message"}
+
+	setField := func(name string, value ast.Expression) ast.Statement {
+		this := &ast.This{Keyword: token.Token{Kind: token.KindThis, Lexeme: "this"}}
+		i.locals[this] = 1
+
+		return &ast.ExpressionStatement{Expression: &ast.Set{
+			Object: this,
+			Name:   token.Token{Kind: token.KindIdentifier, Lexeme: name},
+			Value:  value,
+		}}
+	}
+
+	argument := &ast.Variable{Identifier: message}
+	i.locals[argument] = 0
+
+	initializer := &LoxFunction{
+		Declaration: &ast.FunctionStatement{
+			Name:     token.Token{Kind: token.KindIdentifier, Lexeme: "init"},
+			Params:   []token.Token{message},
+			Defaults: []ast.Expression{nil},
+			Body: []ast.Statement{
+				setField("message", argument),
+				setField("line", &ast.Literal{Value: nil}),
+			},
+		},
+		Closure:       i.builtins,
+		IsInitializer: true,
+	}
+
+	return &LoxClass{
+		Name:    "Error",
+		Methods: map[string]*LoxFunction{"init": initializer},
+	}
+}
+
+// isError reports whether "value" is an instance of Error or one of its
+// subclasses.
+func (i *Interpreter) isError(value interface{}) bool {
+	instance, ok := value.(*LoxInstance)
+	if !ok {
+		return false
+	}
+
+	for class := instance.Class; class != nil; class = class.Superclass {
+		if class == i.errorClass {
+			return true
+		}
+	}
+
+	return false
+}
+
+// exceptionValue returns the value that a catch clause receives for
+// "err", or false if "err" can't be caught (e.g. because it's a return
+// statement unwinding the stack).
+func (i *Interpreter) exceptionValue(err error) (*ast.Literal, bool) {
+	var thrown *thrownError
+	if errors.As(err, &thrown) {
+		return thrown.Value, true
+	}
+
+	var runtimeErr *Error
+	if errors.As(err, &runtimeErr) {
+		instance := &LoxInstance{
+			Class: i.errorClass,
+			fields: map[string]*ast.Literal{
+				"message": {Value: runtimeErr.Message},
+				"line":    {Value: float64(runtimeErr.Token.Line)},
+			},
+		}
+
+		return &ast.Literal{Value: instance}, true
+	}
+
+	return nil, false
+}
+
+// describeException formats a thrown value for an uncaught exception
+// error. Instances with a string "message" field are described by it.
+func describeException(value interface{}) string {
+	if instance, ok := value.(*LoxInstance); ok {
+		if message, found := instance.fields["message"]; found {
+			if s, ok := message.Value.(string); ok {
+				return s
+			}
+		}
+	}
+
+	return repr(value)
+}
@@ -0,0 +1,137 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExceptions(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+try {
+  throw Error("boom");
+} catch (e) {
+  record(e.message);
+  record(e.line);
+}
+
+class NotFound < Error {}
+try {
+  throw NotFound("missing");
+} catch (e) {
+  record(str(e));
+  record(e.message);
+}
+
+class HttpError < Error {
+  init(status) {
+    super.init("status " + str(status));
+    this.status = status;
+  }
+}
+try {
+  throw HttpError(404);
+} catch (e) {
+  record(e.message);
+  record(e.status);
+}
+
+// runtime errors are caught as Errors too
+try {
+  [1][5];
+} catch (e) {
+  record(e.message);
+}
+
+// any value can be thrown
+try { throw [1]; } catch (e) { record(e); }
+
+// finally runs whether or not the body throws, and rethrowing reaches
+// the enclosing try
+try {
+  try {
+    throw "inner";
+  } catch (e) {
+    record("caught " + e);
+    throw e + " again";
+  } finally {
+    record("finally");
+  }
+} catch (e) {
+  record(e);
+}
+
+// returns, breaks and continues pass through try without being caught
+fun early() {
+  try {
+    return "returned";
+  } catch (e) {
+    return "caught";
+  } finally {
+    record("finally before return");
+  }
+}
+record(early());
+
+for (var i = 0; i < 3; i = i + 1) {
+  try {
+    if (i == 0) continue;
+    if (i == 2) break;
+    record(i);
+  } catch (e) {
+    record("caught jump");
+  }
+}
+
+// an error in finally replaces the body's result
+fun overridden() {
+  try {
+    return 1;
+  } finally {
+    throw "from finally";
+  }
+}
+try { overridden(); } catch (e) { record(e); }
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	expected := []interface{}{
+		"boom",
+		2.0,
+		"NotFound instance",
+		"missing",
+		"status 404",
+		404.0,
+		"List index 5 out of range for list of length 1.",
+		&LoxList{Elements: []interface{}{1.0}},
+		"caught inner",
+		"finally",
+		"inner again",
+		"finally before return",
+		"returned",
+		1.0,
+		"from finally",
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestUncaughtException(t *testing.T) {
+	err := run(t, `
+fun fail() {
+  throw Error("boom");
+}
+fail();
+`)
+
+	expected := "[line 2] Uncaught exception: boom"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
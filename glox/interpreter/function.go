@@ -6,38 +6,69 @@ import (
 
 	"github.com/ggilmore/bradfield-languages/glox/ast"
 	"github.com/ggilmore/bradfield-languages/glox/env"
+	"github.com/ggilmore/bradfield-languages/glox/token"
 )
 
 type LoxCallable interface {
-	Arity() int
+	// MinArity and MaxArity are the fewest and most arguments that the
+	// callable accepts. MaxArity is negative if there's no limit.
+	MinArity() int
+	MaxArity() int
 	Call(i *Interpreter, arguments []ast.Expression) (ast.Expression, error)
 	String() string
 }
 
 type LoxFunction struct {
-	Declaration *ast.FunctionStatement
-	Closure     *env.Environment
+	Declaration   *ast.FunctionStatement
+	Closure       *env.Environment
+	IsInitializer bool
 }
 
-func (f *LoxFunction) Arity() int {
+// Bind returns a copy of the function whose closure has "this"
+// defined as the given instance.
+func (f *LoxFunction) Bind(instance *LoxInstance) *LoxFunction {
+	environment := env.New(f.Closure)
+	environment.Define("this", &ast.Literal{Value: instance})
+
+	return &LoxFunction{
+		Declaration:   f.Declaration,
+		Closure:       environment,
+		IsInitializer: f.IsInitializer,
+	}
+}
+
+// MinArity is the number of parameters before the first one with a
+// default value.
+func (f *LoxFunction) MinArity() int {
+	for i, d := range f.Declaration.Defaults {
+		if d != nil {
+			return i
+		}
+	}
+
+	return len(f.Declaration.Params)
+}
+
+func (f *LoxFunction) MaxArity() int {
+	if f.Declaration.Rest != nil {
+		return -1
+	}
+
 	return len(f.Declaration.Params)
 }
 
 func (f *LoxFunction) Call(interpreter *Interpreter, arguments []ast.Expression) (ast.Expression, error) {
 	environment := env.New(f.Closure)
 
-	parameters := f.Declaration.Params
-	for i := 0; i < len(parameters); i++ {
-		name := parameters[i].Lexeme
-		value := arguments[i]
-
-		environment.Define(name, value)
+	err := interpreter.bindParameters(f.Declaration, arguments, environment)
+	if err != nil {
+		return nil, err
 	}
 
 	var result ast.Expression = &ast.Literal{Value: nil}
 
 	body := f.Declaration.Body
-	err := interpreter.executeBlock(body, environment)
+	err = interpreter.executeBlock(body, environment)
 	if err != nil {
 		var rawVal returnValue
 		if !errors.As(err, &rawVal) {
@@ -47,10 +78,21 @@ func (f *LoxFunction) Call(interpreter *Interpreter, arguments []ast.Expression)
 		result = rawVal.ReturnValue()
 	}
 
+	if f.IsInitializer {
+		// initializers always return the instance that they're
+		// initializing, even if they "return;" early
+		this, _ := f.Closure.GetAt(0, "this")
+		return this, nil
+	}
+
 	return result, nil
 }
 
 func (f *LoxFunction) String() string {
+	if f.Declaration.Name.Kind == token.KindFun {
+		return "<fn anonymous >"
+	}
+
 	return fmt.Sprintf("<fn %s >", f.Declaration.Name.Lexeme)
 }
 
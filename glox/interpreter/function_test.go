@@ -0,0 +1,80 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParameters(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+fun f(a, b = a + 1, ...rest) { return [a, b, rest]; }
+record(f(1));
+record(f(1, 5, 6, 7));
+record(f(...[1, 2], ...[3]));
+try { f(); } catch (e) { record(e.message); }
+
+fun g(a, b = 0) {}
+try { g(1, 2, 3); } catch (e) { record(e.message); }
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	list := func(elements ...interface{}) *LoxList {
+		return &LoxList{Elements: elements}
+	}
+
+	expected := []interface{}{
+		list(1.0, 2.0, list()),
+		list(1.0, 5.0, list(6.0, 7.0)),
+		list(1.0, 2.0, list(3.0)),
+		"Expected at least 1 arguments but got 0.",
+		"Expected 1 to 2 arguments but got 3.",
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestLambdas(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+fun makeCounter() {
+  var count = 0;
+  return fun() { count = count + 1; return count; };
+}
+
+var counter = makeCounter();
+counter();
+record(counter());
+
+// each call gets its own closure
+record(makeCounter()());
+
+fun apply(f, x) { return f(x); }
+record(apply(fun (x) { return x * 2; }, 21));
+
+record((fun (a, b) { return a + b; })(1, 2));
+record(str(fun () {}));
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	expected := []interface{}{
+		2.0,
+		1.0,
+		42.0,
+		3.0,
+		"<fn anonymous >",
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
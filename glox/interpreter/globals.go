@@ -1,26 +1,48 @@
 package interpreter
 
 import (
-	"time"
+	"math"
+	"strconv"
+	"strings"
 
 	"github.com/ggilmore/bradfield-languages/glox/ast"
 )
 
-type clock struct{}
-
-func (c *clock) Arity() int {
-	return 0
+// builtinNatives are the natives that every interpreter defines.
+var builtinNatives = [][]*Native{
+	{
+		{Name: "clock", Fn: clock},
+		{Name: "timer", Fn: timer},
+		{Name: "str", Params: []Type{TypeAny}, Fn: str},
+		{Name: "num", Params: []Type{TypeNumber | TypeString}, Fn: num},
+		{Name: "type", Params: []Type{TypeAny}, Fn: typeOf},
+	},
+	jsonNatives,
+	randomNatives,
 }
 
-func (c *clock) Call(_ *Interpreter, _ []ast.Expression) (ast.Expression, error) {
-	t := float64(time.Now().Unix())
-	return &ast.Literal{Value: t}, nil
+// str converts a value to a string the same way that print does.
+func str(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	return arguments[0].Output(), nil
 }
 
-func (c *clock) String() string {
-	return "<native fn>"
-}
+// num parses a string as a number, ignoring surrounding whitespace. It
+// returns nil if the string isn't a (finite) number.
+func num(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	s, ok := arguments[0].Value.(string)
+	if !ok {
+		return arguments[0].Value, nil
+	}
 
-var clockFunction = &ast.Literal{Value: &clock{}}
+	n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+		return nil, nil
+	}
 
-var _ LoxCallable = &clock{}
+	return n, nil
+}
+
+// typeOf returns the name of a value's type, e.g. "number".
+func typeOf(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	return typeName(arguments[0].Value), nil
+}
@@ -1,8 +1,12 @@
 package interpreter
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"math/rand"
 	"os"
+	"time"
 
 	"github.com/ggilmore/bradfield-languages/glox/ast"
 	"github.com/ggilmore/bradfield-languages/glox/env"
@@ -10,26 +14,71 @@ import (
 )
 
 type Interpreter struct {
-	globals *env.Environment
-	env     *env.Environment
-	locals  map[ast.Expression]int
+	// builtins holds the native functions, which every module can see
+	// unless it shadows them with a global of its own
+	builtins *env.Environment
+	env      *env.Environment
+	locals   map[ast.Expression]int
+
+	searchPath []string
+	modules    map[string]*LoxModule
+
+	// importing holds the paths of the files that are currently being
+	// run, with the innermost import last
+	importing []string
+
+	now     func() time.Time
+	started time.Time
+	random  *rand.Rand
+
+	errorClass *LoxClass
 }
 
-func New() *Interpreter {
-	globals := env.New(nil)
-	globals.Define("clock", clockFunction)
+func New(options ...Option) *Interpreter {
+	builtins := env.New(nil)
+	builtins.Define("math", &ast.Literal{Value: mathModule()})
+	builtins.Define("regex", &ast.Literal{Value: newNativeModule("regex", regexNatives)})
+	builtins.Define("date", &ast.Literal{Value: dateModule()})
+	builtins.Define("string", &ast.Literal{Value: newNativeModule("string", stringNatives)})
+	builtins.Define("list", &ast.Literal{Value: newNativeModule("list", listNatives)})
+	builtins.Define("map", &ast.Literal{Value: newNativeModule("map", mapNatives)})
+
+	for _, natives := range builtinNatives {
+		for _, n := range natives {
+			builtins.Define(n.Name, &ast.Literal{Value: n})
+		}
+	}
+
+	i := &Interpreter{
+		builtins: builtins,
+		env:      env.New(nil),
+		locals:   make(map[ast.Expression]int),
+		modules:  make(map[string]*LoxModule),
+		now:      time.Now,
+		random:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	i.errorClass = i.newErrorClass()
+	builtins.Define("Error", &ast.Literal{Value: i.errorClass})
 
-	return &Interpreter{
-		globals: globals,
-		env:     globals,
-		locals:  make(map[ast.Expression]int),
+	for _, o := range options {
+		o(i)
 	}
+
+	i.started = i.now()
+
+	return i
 }
 
 func (i *Interpreter) Interpret(statements []ast.Statement) error {
 	for _, s := range statements {
 		err := i.execute(s)
 		if err != nil {
+			var thrown *thrownError
+			if errors.As(err, &thrown) {
+				return &Error{thrown.Token, fmt.Sprintf("Uncaught exception: %s", describeException(thrown.Value.Value))}
+			}
+
 			return err
 		}
 	}
@@ -49,16 +98,34 @@ func (i *Interpreter) execute(stmt ast.Statement) error {
 		return i.expressionStmt(s)
 	case *ast.VarStatement:
 		return i.varStmt(s)
+	case *ast.DestructureStatement:
+		return i.destructureStmt(s)
 	case *ast.BlockStatement:
 		return i.blockStmt(s)
 	case *ast.IfStatement:
 		return i.ifStmt(s)
 	case *ast.WhileStatement:
 		return i.whileStmt(s)
+	case *ast.ForInStatement:
+		return i.forInStmt(s)
 	case *ast.FunctionStatement:
 		return i.functionStmt(s)
 	case *ast.ReturnStatement:
 		return i.returnStmt(s)
+	case *ast.ClassStatement:
+		return i.classStmt(s)
+	case *ast.BreakStatement:
+		return i.breakStmt(s)
+	case *ast.ContinueStatement:
+		return i.continueStmt(s)
+	case *ast.MatchStatement:
+		return i.matchStmt(s)
+	case *ast.ThrowStatement:
+		return i.throwStmt(s)
+	case *ast.TryStatement:
+		return i.tryStmt(s)
+	case *ast.ImportStatement:
+		return i.importStmt(s)
 	}
 
 	panic(fmt.Sprintf("unhandled statement type %+v", stmt))
@@ -70,7 +137,7 @@ func (i *Interpreter) expressionStmt(e *ast.ExpressionStatement) error {
 }
 
 func (i *Interpreter) returnStmt(r *ast.ReturnStatement) error {
-	var value ast.Expression
+	var value ast.Expression = &ast.Literal{Value: nil}
 	if r.Value != nil {
 		expr, err := i.evaluate(r.Value)
 		if err != nil {
@@ -94,6 +161,11 @@ func (i *Interpreter) printStmt(p *ast.PrintStatement) error {
 }
 
 func (i *Interpreter) varStmt(v *ast.VarStatement) error {
+	err := i.checkRedeclaration(v.Name)
+	if err != nil {
+		return err
+	}
+
 	name := v.Name.Lexeme
 	var rawValue ast.Expression = &ast.Literal{Value: nil}
 
@@ -106,6 +178,11 @@ func (i *Interpreter) varStmt(v *ast.VarStatement) error {
 		return err
 	}
 
+	if v.Const {
+		i.env.DefineConstant(name, value)
+		return nil
+	}
+
 	i.env.Define(name, value)
 	return nil
 }
@@ -143,14 +220,163 @@ func (i *Interpreter) whileStmt(w *ast.WhileStatement) error {
 		}
 
 		err = i.execute(w.Body)
+		if err != nil {
+			var brk *breakError
+			if errors.As(err, &brk) && targetsLoop(w.Label, brk.Label) {
+				break
+			}
+
+			var cont *continueError
+			if !errors.As(err, &cont) || !targetsLoop(w.Label, cont.Label) {
+				return err
+			}
+		}
+
+		if w.Increment != nil {
+			_, err := i.evaluate(w.Increment)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (i *Interpreter) matchStmt(m *ast.MatchStatement) error {
+	subject, err := i.evaluate(m.Subject)
+	if err != nil {
+		return err
+	}
+
+	for _, arm := range m.Arms {
+		matched, err := i.matchArm(arm, subject.Value)
 		if err != nil {
 			return err
 		}
+
+		if matched {
+			return nil
+		}
+	}
+
+	if m.Else != nil {
+		return i.executeBlock([]ast.Statement{m.Else}, env.New(i.env))
 	}
 
 	return nil
 }
 
+// matchArm runs the arm's body if it matches "subject", and reports
+// whether it did. Each arm gets its own scope, which its guard is
+// evaluated in as well.
+func (i *Interpreter) matchArm(arm *ast.MatchArm, subject interface{}) (bool, error) {
+	matched := false
+	for _, p := range arm.Patterns {
+		if patternMatches(p, subject) {
+			matched = true
+			break
+		}
+	}
+
+	if !matched {
+		return false, nil
+	}
+
+	originalEnv := i.env
+	defer func() {
+		i.env = originalEnv
+	}()
+
+	i.env = env.New(i.env)
+
+	if arm.Guard != nil {
+		guard, err := i.evaluate(arm.Guard)
+		if err != nil {
+			return false, err
+		}
+
+		if !isTruthy(guard.Value) {
+			return false, nil
+		}
+	}
+
+	return true, i.execute(arm.Body)
+}
+
+func (i *Interpreter) throwStmt(t *ast.ThrowStatement) error {
+	value, err := i.evaluate(t.Value)
+	if err != nil {
+		return err
+	}
+
+	if i.isError(value.Value) {
+		instance := value.Value.(*LoxInstance)
+		if line, found := instance.fields["line"]; !found || line.Value == nil {
+			instance.fields["line"] = &ast.Literal{Value: float64(t.Keyword.Line)}
+		}
+	}
+
+	return &thrownError{Token: t.Keyword, Value: value}
+}
+
+// tryStmt runs the try statement's body, handing any exception to its
+// catch clause. Returns, breaks and continues aren't exceptions, so they
+// pass straight through (after running the finally clause).
+func (i *Interpreter) tryStmt(t *ast.TryStatement) error {
+	err := i.blockStmt(t.Body)
+
+	if err != nil && t.Catch != nil {
+		value, ok := i.exceptionValue(err)
+		if ok {
+			environment := env.New(i.env)
+			environment.Define(t.CatchName.Lexeme, value)
+
+			err = i.executeBlock(t.Catch.Statements, environment)
+		}
+	}
+
+	if t.Finally != nil {
+		// if the finally clause itself throws or jumps somewhere,
+		// that replaces whatever the rest of the statement did
+		finallyErr := i.blockStmt(t.Finally)
+		if finallyErr != nil {
+			return finallyErr
+		}
+	}
+
+	return err
+}
+
+func (i *Interpreter) breakStmt(b *ast.BreakStatement) error {
+	var label string
+	if b.Label != nil {
+		label = b.Label.Lexeme
+	}
+
+	return &breakError{Label: label}
+}
+
+func (i *Interpreter) continueStmt(c *ast.ContinueStatement) error {
+	var label string
+	if c.Label != nil {
+		label = c.Label.Lexeme
+	}
+
+	return &continueError{Label: label}
+}
+
+// targetsLoop reports whether a break or continue with the given label
+// applies to the loop labeled "loopLabel" (which is nil for unlabeled
+// loops). Unlabeled jumps always apply to the innermost loop.
+func targetsLoop(loopLabel *token.Token, label string) bool {
+	if label == "" {
+		return true
+	}
+
+	return loopLabel != nil && loopLabel.Lexeme == label
+}
+
 func (i *Interpreter) blockStmt(b *ast.BlockStatement) error {
 	return i.executeBlock(b.Statements, env.New(i.env))
 }
@@ -174,6 +400,11 @@ func (i *Interpreter) executeBlock(statements []ast.Statement, environment *env.
 }
 
 func (i *Interpreter) functionStmt(f *ast.FunctionStatement) error {
+	err := i.checkRedeclaration(f.Name)
+	if err != nil {
+		return err
+	}
+
 	function := LoxFunction{
 		Declaration: f,
 		Closure:     i.env,
@@ -184,6 +415,53 @@ func (i *Interpreter) functionStmt(f *ast.FunctionStatement) error {
 	return nil
 }
 
+func (i *Interpreter) classStmt(c *ast.ClassStatement) error {
+	err := i.checkRedeclaration(c.Name)
+	if err != nil {
+		return err
+	}
+
+	var superclass *LoxClass
+	if c.Superclass != nil {
+		value, err := i.evaluate(c.Superclass)
+		if err != nil {
+			return err
+		}
+
+		class, ok := value.Value.(*LoxClass)
+		if !ok {
+			return &Error{c.Superclass.Identifier, "Superclass must be a class."}
+		}
+
+		superclass = class
+
+		i.env = env.New(i.env)
+		i.env.Define("super", &ast.Literal{Value: superclass})
+	}
+
+	methods := make(map[string]*LoxFunction)
+	for _, m := range c.Methods {
+		methods[m.Name.Lexeme] = &LoxFunction{
+			Declaration:   m,
+			Closure:       i.env,
+			IsInitializer: m.Name.Lexeme == "init",
+		}
+	}
+
+	class := &LoxClass{
+		Name:       c.Name.Lexeme,
+		Superclass: superclass,
+		Methods:    methods,
+	}
+
+	if superclass != nil {
+		i.env = i.env.Parent
+	}
+
+	i.env.Define(c.Name.Lexeme, &ast.Literal{Value: class})
+	return nil
+}
+
 func (i *Interpreter) evaluate(expr ast.Expression) (*ast.Literal, error) {
 	switch e := expr.(type) {
 	case *ast.Literal:
@@ -206,6 +484,38 @@ func (i *Interpreter) evaluate(expr ast.Expression) (*ast.Literal, error) {
 		return i.debug(e)
 	case *ast.Call:
 		return i.call(e)
+	case *ast.Get:
+		return i.get(e)
+	case *ast.Set:
+		return i.set(e)
+	case *ast.This:
+		return i.this(e)
+	case *ast.Super:
+		return i.super(e)
+	case *ast.Lambda:
+		return i.lambda(e)
+	case *ast.List:
+		return i.list(e)
+	case *ast.Index:
+		return i.index(e)
+	case *ast.IndexSet:
+		return i.indexSet(e)
+	case *ast.Map:
+		return i.mapLiteral(e)
+	case *ast.Stringify:
+		return i.stringify(e)
+	case *ast.CompoundAssignment:
+		return i.compoundAssignment(e)
+	case *ast.Update:
+		return i.update(e)
+	case *ast.Conditional:
+		return i.conditional(e)
+	case *ast.Tuple:
+		return i.tuple(e)
+	case *ast.Destructure:
+		return i.destructure(e)
+	case *ast.Range:
+		return i.rangeExpr(e)
 	}
 
 	panic(fmt.Sprintf("unhandled expression type %+v", expr))
@@ -227,12 +537,29 @@ func (i *Interpreter) call(c *ast.Call) (*ast.Literal, error) {
 
 	var arguments []ast.Expression
 	for _, rawArg := range c.Arguments {
+		spread, isSpread := rawArg.(*ast.Spread)
+		if isSpread {
+			rawArg = spread.Value
+		}
+
 		arg, err := i.evaluate(rawArg)
 		if err != nil {
 			return nil, err
 		}
 
-		arguments = append(arguments, arg)
+		if !isSpread {
+			arguments = append(arguments, arg)
+			continue
+		}
+
+		elements, ok := unpack(arg.Value)
+		if !ok {
+			return nil, &Error{spread.Ellipsis, fmt.Sprintf("Can only spread lists and tuples, got %s.", typeName(arg.Value))}
+		}
+
+		for _, e := range elements {
+			arguments = append(arguments, &ast.Literal{Value: e})
+		}
 	}
 
 	function, ok := callee.Value.(LoxCallable)
@@ -240,9 +567,33 @@ func (i *Interpreter) call(c *ast.Call) (*ast.Literal, error) {
 		return nil, &Error{c.Paren, "Can only call functions and classes."}
 	}
 
-	if len(arguments) != function.Arity() {
-		message := fmt.Sprintf("Expected %d arguments but got %d.", function.Arity(), len(arguments))
-		return nil, &Error{c.Paren, message}
+	result, err := i.callFunction(function, arguments)
+	if err != nil {
+		var nativeErr *nativeError
+		if errors.As(err, &nativeErr) {
+			return nil, &Error{c.Paren, nativeErr.Message}
+		}
+
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// callFunction calls "function" with already evaluated arguments. Errors
+// that aren't tied to a location in the script (such as an arity mismatch)
+// are returned as nativeErrors.
+func (i *Interpreter) callFunction(function LoxCallable, arguments []ast.Expression) (*ast.Literal, error) {
+	min, max := function.MinArity(), function.MaxArity()
+	if len(arguments) < min || (max >= 0 && len(arguments) > max) {
+		switch {
+		case min == max:
+			return nil, nativeErrorf("Expected %d arguments but got %d.", min, len(arguments))
+		case max < 0:
+			return nil, nativeErrorf("Expected at least %d arguments but got %d.", min, len(arguments))
+		default:
+			return nil, nativeErrorf("Expected %d to %d arguments but got %d.", min, max, len(arguments))
+		}
 	}
 
 	result, err := function.Call(i, arguments)
@@ -253,6 +604,251 @@ func (i *Interpreter) call(c *ast.Call) (*ast.Literal, error) {
 	return i.evaluate(result)
 }
 
+// bindParameters defines the function's parameters in "environment".
+// Parameters that weren't passed get their default values, which are
+// evaluated in "environment" so that they can refer to the parameters
+// before them.
+func (i *Interpreter) bindParameters(f *ast.FunctionStatement, arguments []ast.Expression, environment *env.Environment) error {
+	originalEnv := i.env
+	defer func() {
+		i.env = originalEnv
+	}()
+
+	i.env = environment
+
+	for n, param := range f.Params {
+		if n < len(arguments) {
+			environment.Define(param.Lexeme, arguments[n])
+			continue
+		}
+
+		value, err := i.evaluate(f.Defaults[n])
+		if err != nil {
+			return err
+		}
+
+		environment.Define(param.Lexeme, value)
+	}
+
+	if f.Rest != nil {
+		rest := &LoxList{}
+		for n := len(f.Params); n < len(arguments); n++ {
+			rest.Elements = append(rest.Elements, arguments[n].(*ast.Literal).Value)
+		}
+
+		environment.Define(f.Rest.Lexeme, &ast.Literal{Value: rest})
+	}
+
+	return nil
+}
+
+func (i *Interpreter) get(g *ast.Get) (*ast.Literal, error) {
+	object, err := i.evaluate(g.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	switch o := object.Value.(type) {
+	case *LoxInstance:
+		return o.Get(g.Name)
+	case *LoxModule:
+		return o.Get(g.Name)
+	}
+
+	return nil, &Error{g.Name, "Only instances and modules have properties."}
+}
+
+func (i *Interpreter) set(s *ast.Set) (*ast.Literal, error) {
+	object, err := i.evaluate(s.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, ok := object.Value.(*LoxInstance)
+	if !ok {
+		return nil, &Error{s.Name, "Only instances have fields."}
+	}
+
+	value, err := i.evaluate(s.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	instance.Set(s.Name, value)
+	return value, nil
+}
+
+func (i *Interpreter) this(t *ast.This) (*ast.Literal, error) {
+	rawValue, defined := i.lookUpVariable(t, t.Keyword)
+	if !defined {
+		return nil, &Error{t.Keyword, "undefined variable \"this\""}
+	}
+
+	return i.evaluate(rawValue)
+}
+
+func (i *Interpreter) super(s *ast.Super) (*ast.Literal, error) {
+	distance := i.locals[s]
+
+	rawSuperclass, _ := i.env.GetAt(distance, "super")
+	superclass := rawSuperclass.(*ast.Literal).Value.(*LoxClass)
+
+	// "this" is always bound in the scope just inside of the one
+	// that defines "super"
+	rawObject, _ := i.env.GetAt(distance-1, "this")
+	object := rawObject.(*ast.Literal).Value.(*LoxInstance)
+
+	method, found := superclass.FindMethod(s.Method.Lexeme)
+	if !found {
+		return nil, &Error{s.Method, fmt.Sprintf("Undefined property %q.", s.Method.Lexeme)}
+	}
+
+	return &ast.Literal{Value: method.Bind(object)}, nil
+}
+
+func (i *Interpreter) lambda(l *ast.Lambda) (*ast.Literal, error) {
+	function := &LoxFunction{
+		Declaration: l.Declaration,
+		Closure:     i.env,
+	}
+
+	return &ast.Literal{Value: function}, nil
+}
+
+func (i *Interpreter) list(l *ast.List) (*ast.Literal, error) {
+	list := &LoxList{}
+	for _, rawElement := range l.Elements {
+		element, err := i.evaluate(rawElement)
+		if err != nil {
+			return nil, err
+		}
+
+		list.Elements = append(list.Elements, element.Value)
+	}
+
+	return &ast.Literal{Value: list}, nil
+}
+
+func (i *Interpreter) index(e *ast.Index) (*ast.Literal, error) {
+	object, err := i.evaluate(e.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := i.evaluate(e.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	return getIndex(e.Bracket, object, index)
+}
+
+func getIndex(bracket token.Token, object, index *ast.Literal) (*ast.Literal, error) {
+	switch o := object.Value.(type) {
+	case *LoxList:
+		n, err := o.index(bracket, index.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ast.Literal{Value: o.Elements[n]}, nil
+	case *LoxTuple:
+		n, err := checkIndex(bracket, "Tuple", index.Value, len(o.Elements))
+		if err != nil {
+			return nil, err
+		}
+
+		return &ast.Literal{Value: o.Elements[n]}, nil
+	case *LoxMap:
+		// missing keys evaluate to nil, use has() to tell them apart
+		// from keys that are explicitly set to nil
+		value, _ := o.Get(index.Value)
+		return &ast.Literal{Value: value}, nil
+	}
+
+	return nil, &Error{bracket, "Only lists, tuples and maps can be indexed."}
+}
+
+func (i *Interpreter) indexSet(e *ast.IndexSet) (*ast.Literal, error) {
+	object, err := i.evaluate(e.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := i.evaluate(e.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := i.evaluate(e.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	err = setIndex(e.Bracket, object, index, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func setIndex(bracket token.Token, object, index, value *ast.Literal) error {
+	switch o := object.Value.(type) {
+	case *LoxList:
+		n, err := o.index(bracket, index.Value)
+		if err != nil {
+			return err
+		}
+
+		o.Elements[n] = value.Value
+		return nil
+	case *LoxMap:
+		if message, ok := validMapKey(index.Value); !ok {
+			return &Error{bracket, message}
+		}
+
+		o.Set(index.Value, value.Value)
+		return nil
+	case *LoxTuple:
+		return &Error{bracket, "Tuples can't be modified."}
+	}
+
+	return &Error{bracket, "Only lists, tuples and maps can be indexed."}
+}
+
+func (i *Interpreter) mapLiteral(m *ast.Map) (*ast.Literal, error) {
+	out := NewMap()
+	for n := range m.Keys {
+		key, err := i.evaluate(m.Keys[n])
+		if err != nil {
+			return nil, err
+		}
+
+		if message, ok := validMapKey(key.Value); !ok {
+			return nil, &Error{m.Brace, message}
+		}
+
+		value, err := i.evaluate(m.Values[n])
+		if err != nil {
+			return nil, err
+		}
+
+		out.Set(key.Value, value.Value)
+	}
+
+	return &ast.Literal{Value: out}, nil
+}
+
+func (i *Interpreter) stringify(s *ast.Stringify) (*ast.Literal, error) {
+	value, err := i.evaluate(s.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.Literal{Value: value.Output()}, nil
+}
+
 func (i *Interpreter) let(l *ast.Let) (*ast.Literal, error) {
 	originalEnv := i.env
 	defer func() {
@@ -274,25 +870,201 @@ func (i *Interpreter) assignment(a *ast.Assignment) (*ast.Literal, error) {
 		return nil, err
 	}
 
-	found := i.setVariable(a, a.Name, value)
-	if !found {
-		return nil, &Error{a.Name, fmt.Sprintf("undefined variable %q", a.Name.Lexeme)}
+	err = i.assign(a, a.Name, value)
+	if err != nil {
+		return nil, err
 	}
 
 	return value, nil
 }
 
+// assign sets the variable "name" that's referenced by the expression "e".
+func (i *Interpreter) assign(e ast.Expression, name token.Token, value *ast.Literal) error {
+	// the resolver has already checked that local variables aren't
+	// constants, so only globals need to be checked here
+	if _, isLocal := i.locals[e]; !isLocal && i.env.Root().IsConstant(name.Lexeme) {
+		return &Error{name, fmt.Sprintf("Can't assign to constant %q.", name.Lexeme)}
+	}
+
+	found := i.setVariable(e, name, value)
+	if !found {
+		return &Error{name, fmt.Sprintf("undefined variable %q", name.Lexeme)}
+	}
+
+	return nil
+}
+
+// checkRedeclaration reports an error if "name" is already a constant in
+// the current scope. The resolver has already checked local scopes, so
+// this only catches global constants.
+func (i *Interpreter) checkRedeclaration(name token.Token) error {
+	if i.env.IsConstant(name.Lexeme) {
+		return &Error{name, fmt.Sprintf("Can't redeclare constant %q.", name.Lexeme)}
+	}
+
+	return nil
+}
+
+// compoundOperators maps each compound assignment operator to the binary
+// operator that it applies.
+var compoundOperators = map[token.Kind]token.Kind{
+	token.KindPlusEqual:  token.KindPlus,
+	token.KindMinusEqual: token.KindMinus,
+	token.KindStarEqual:  token.KindStar,
+	token.KindSlashEqual: token.KindSlash,
+	token.KindPlusPlus:   token.KindPlus,
+	token.KindMinusMinus: token.KindMinus,
+}
+
+func (i *Interpreter) compoundAssignment(c *ast.CompoundAssignment) (*ast.Literal, error) {
+	get, set, err := i.reference(c.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := get()
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := i.evaluate(c.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	operator := c.Operator
+	operator.Kind = compoundOperators[c.Operator.Kind]
+
+	result, err := binaryOperation(operator, current, value)
+	if err != nil {
+		return nil, err
+	}
+
+	err = set(result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (i *Interpreter) update(u *ast.Update) (*ast.Literal, error) {
+	get, set, err := i.reference(u.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := get()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := current.Value.(float64); !ok {
+		return nil, nanError(u.Operator, current)
+	}
+
+	operator := u.Operator
+	operator.Kind = compoundOperators[u.Operator.Kind]
+
+	result, err := binaryOperation(operator, current, &ast.Literal{Value: 1.0})
+	if err != nil {
+		return nil, err
+	}
+
+	err = set(result)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Prefix {
+		return result, nil
+	}
+
+	return current, nil
+}
+
+// reference evaluates the parts of an assignment target (a Variable, Get or
+// Index expression) that determine where it points, and returns functions
+// that read and write that location. This lets read-modify-write
+// operations evaluate the target's object and index only once.
+func (i *Interpreter) reference(target ast.Expression) (get func() (*ast.Literal, error), set func(*ast.Literal) error, err error) {
+	switch t := target.(type) {
+	case *ast.Variable:
+		get = func() (*ast.Literal, error) {
+			return i.variable(t)
+		}
+
+		set = func(value *ast.Literal) error {
+			return i.assign(t, t.Identifier, value)
+		}
+
+		return get, set, nil
+
+	case *ast.Get:
+		object, err := i.evaluate(t.Object)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		instance, ok := object.Value.(*LoxInstance)
+		if !ok {
+			return nil, nil, &Error{t.Name, "Only instances have fields."}
+		}
+
+		get = func() (*ast.Literal, error) {
+			return instance.Get(t.Name)
+		}
+
+		set = func(value *ast.Literal) error {
+			instance.Set(t.Name, value)
+			return nil
+		}
+
+		return get, set, nil
+
+	case *ast.Index:
+		object, err := i.evaluate(t.Object)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		index, err := i.evaluate(t.Index)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		get = func() (*ast.Literal, error) {
+			return getIndex(t.Bracket, object, index)
+		}
+
+		set = func(value *ast.Literal) error {
+			return setIndex(t.Bracket, object, index, value)
+		}
+
+		return get, set, nil
+	}
+
+	panic(fmt.Sprintf("unhandled assignment target %+v", target))
+}
+
 func (i *Interpreter) logical(l *ast.Logical) (*ast.Literal, error) {
 	left, err := i.evaluate(l.Left)
 	if err != nil {
 		return nil, err
 	}
 
-	if l.Operator.Kind == token.KindOr {
+	switch l.Operator.Kind {
+	case token.KindOr:
 		if isTruthy(left.Value) {
 			return left, nil
 		}
-	} else {
+	case token.KindQuestionQuestion:
+		// unlike "or", "??" only falls back to the right operand
+		// when the left one is nil, not when it's false
+		if left.Value != nil {
+			return left, nil
+		}
+	default:
 		if !isTruthy(left.Value) {
 			return left, nil
 		}
@@ -301,6 +1073,19 @@ func (i *Interpreter) logical(l *ast.Logical) (*ast.Literal, error) {
 	return i.evaluate(l.Right)
 }
 
+func (i *Interpreter) conditional(c *ast.Conditional) (*ast.Literal, error) {
+	cond, err := i.evaluate(c.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	if isTruthy(cond.Value) {
+		return i.evaluate(c.Then)
+	}
+
+	return i.evaluate(c.Else)
+}
+
 func (i *Interpreter) variable(v *ast.Variable) (*ast.Literal, error) {
 	rawValue, defined := i.lookUpVariable(v, v.Identifier)
 
@@ -317,10 +1102,18 @@ func (i *Interpreter) variable(v *ast.Variable) (*ast.Literal, error) {
 	return value, nil
 }
 
+// lookUpVariable finds the value of a variable. Variables that the
+// resolver didn't find a local definition for are globals of the module
+// that the current code belongs to, or builtins.
 func (i *Interpreter) lookUpVariable(e ast.Expression, name token.Token) (ast.Expression, bool) {
 	distance, found := i.locals[e]
 	if !found {
-		return i.globals.Get(name.Lexeme)
+		value, found := i.env.Root().Get(name.Lexeme)
+		if !found {
+			return i.builtins.Get(name.Lexeme)
+		}
+
+		return value, true
 	}
 
 	return i.env.GetAt(distance, name.Lexeme)
@@ -329,7 +1122,7 @@ func (i *Interpreter) lookUpVariable(e ast.Expression, name token.Token) (ast.Ex
 func (i *Interpreter) setVariable(e ast.Expression, name token.Token, value ast.Expression) bool {
 	distance, found := i.locals[e]
 	if !found {
-		return i.globals.Set(name.Lexeme, value)
+		return i.env.Root().Set(name.Lexeme, value)
 	}
 
 	return i.env.SetAt(distance, name.Lexeme, value)
@@ -354,8 +1147,10 @@ func (i *Interpreter) binary(b *ast.Binary) (*ast.Literal, error) {
 		return nil, err
 	}
 
-	operator := b.Operator
+	return binaryOperation(b.Operator, left, right)
+}
 
+func binaryOperation(operator token.Token, left, right *ast.Literal) (*ast.Literal, error) {
 	switch operator.Kind {
 	case token.KindMinus:
 		if l, ok := left.Value.(float64); ok {
@@ -380,6 +1175,32 @@ func (i *Interpreter) binary(b *ast.Binary) (*ast.Literal, error) {
 			}
 		}
 
+		return nil, nanError(operator, left, right)
+	case token.KindPercent:
+		// like most C-family languages, the result has the
+		// same sign as the dividend
+		if l, ok := left.Value.(float64); ok {
+			if r, ok := right.Value.(float64); ok {
+				return &ast.Literal{Value: math.Mod(l, r)}, nil
+			}
+		}
+
+		return nil, nanError(operator, left, right)
+	case token.KindStarStar:
+		if l, ok := left.Value.(float64); ok {
+			if r, ok := right.Value.(float64); ok {
+				return &ast.Literal{Value: math.Pow(l, r)}, nil
+			}
+		}
+
+		return nil, nanError(operator, left, right)
+	case token.KindTildeSlash:
+		if l, ok := left.Value.(float64); ok {
+			if r, ok := right.Value.(float64); ok {
+				return &ast.Literal{Value: math.Floor(l / r)}, nil
+			}
+		}
+
 		return nil, nanError(operator, left, right)
 	case token.KindPlus:
 		if l, ok := left.Value.(float64); ok {
@@ -468,9 +1289,56 @@ func isEqual(x, y interface{}) bool {
 		return false
 	}
 
+	if t, ok := x.(*LoxTuple); ok {
+		other, ok := y.(*LoxTuple)
+		return ok && t.equal(other)
+	}
+
+	if r, ok := x.(*LoxRange); ok {
+		other, ok := y.(*LoxRange)
+		return ok && *r == *other
+	}
+
 	return x == y
 }
 
+// typeName returns the name of the lox type of "value", for use in
+// error messages.
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case *LoxList:
+		return "list"
+	case *LoxMap:
+		return "map"
+	case *LoxClass:
+		return "class"
+	case *LoxInstance:
+		return "instance"
+	case *LoxModule:
+		return "module"
+	case *LoxRegex:
+		return "regex"
+	case *LoxDate:
+		return "date"
+	case *LoxTuple:
+		return "tuple"
+	case *LoxRange:
+		return "range"
+	case LoxCallable:
+		return "function"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
+
 func isTruthy(candidate interface{}) bool {
 	if candidate == nil {
 		return false
@@ -0,0 +1,219 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBreakAndContinue(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+var i = 0;
+while (true) {
+  i = i + 1;
+  if (i == 2) continue;
+  if (i == 4) break;
+  record(i);
+}
+
+// continue still runs a for loop's increment
+for (var j = 0; j < 4; j = j + 1) {
+  if (j % 2 == 0) continue;
+  record(j);
+}
+
+outer: for (var a = 0; a < 3; a = a + 1) {
+  for (var b = 0; b < 3; b = b + 1) {
+    if (b == 1) continue outer;
+    if (a == 2) break outer;
+    record([a, b]);
+  }
+}
+
+fun first() {
+  while (true) {
+    return "returned";
+  }
+}
+record(first());
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	list := func(elements ...interface{}) *LoxList {
+		return &LoxList{Elements: elements}
+	}
+
+	expected := []interface{}{
+		1.0, 3.0,
+		1.0, 3.0,
+		list(0.0, 0.0),
+		list(1.0, 0.0),
+		"returned",
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestArithmeticOperators(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+record(7 % 3);
+record(-7 % 3);
+record(7.5 % 2);
+record(2 ** 10);
+
+// "**" is right-associative and binds tighter than unary minus
+record(2 ** 3 ** 2);
+record(-2 ** 2);
+record(2 ** -1);
+
+record(7 ~/ 2);
+record(-7 ~/ 2);
+record(1 + 2 * 3 ~/ 4 % 5);
+
+// "//" still starts a comment
+record(6 // 4
+);
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	expected := []interface{}{
+		1.0,
+		-1.0,
+		1.5,
+		1024.0,
+		512.0,
+		-4.0,
+		0.5,
+		3.0,
+		-4.0,
+		2.0,
+		6.0,
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestCompoundAssignment(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+var x = 10;
+x += 5;
+x -= 3;
+x *= 2;
+x /= 4;
+record(x);
+
+record(x++);
+record(x);
+record(++x);
+record(--x ** 2);
+
+class Box {}
+var box = Box();
+box.n = 1;
+box.n += 2;
+box.n++;
+record(box.n);
+
+var xs = [1, 2];
+var calls = 0;
+fun index() { calls++; return 1; }
+
+// the index is only evaluated once
+xs[index()] *= 10;
+xs[index()]++;
+record(xs);
+record(calls);
+
+var m = {"s": "a"};
+m["s"] += "b";
+record(m["s"]);
+
+fun makeCounter() {
+  var count = 0;
+  return fun() { count += 1; return count; };
+}
+var counter = makeCounter();
+counter();
+record(counter());
+
+try { var s = "a"; s++; } catch (e) { record(e.message); }
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	expected := []interface{}{
+		6.0,
+		6.0,
+		7.0,
+		8.0,
+		49.0,
+		4.0,
+		&LoxList{Elements: []interface{}{1.0, 21.0}},
+		2.0,
+		"ab",
+		2.0,
+		"operand <Literal{a}> must be a number",
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestConditionalOperators(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+fun boom() { throw "evaluated"; }
+
+record(true ? "yes" : boom());
+record(false ? boom() : "no");
+record(nil ? 1 : 0);
+
+// the conditional operator is right-associative
+record(false ? 1 : false ? 2 : 3);
+
+record(nil ?? "default");
+record(false ?? "default");
+record(0 ?? boom());
+record(nil ?? nil ?? "last");
+
+var x;
+x = nil ?? 5;
+record(x);
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	expected := []interface{}{
+		"yes",
+		"no",
+		0.0,
+		3.0,
+		"default",
+		false,
+		0.0,
+		"last",
+		5.0,
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
@@ -0,0 +1,225 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+	"github.com/ggilmore/bradfield-languages/glox/env"
+	"github.com/ggilmore/bradfield-languages/glox/token"
+)
+
+// LoxRange is the runtime representation of "start..end", the numbers
+// from Start up to (but not including) End in steps of 1. Inclusive
+// ranges ("start..=end") include End as well.
+type LoxRange struct {
+	Start, End float64
+	Inclusive  bool
+}
+
+// length returns the number of values in the range.
+func (r *LoxRange) length() float64 {
+	if r.Inclusive {
+		return math.Max(0, math.Floor(r.End-r.Start)+1)
+	}
+
+	return math.Max(0, math.Ceil(r.End-r.Start))
+}
+
+func (r *LoxRange) String() string {
+	operator := ".."
+	if r.Inclusive {
+		operator = "..="
+	}
+
+	return repr(r.Start) + operator + repr(r.End)
+}
+
+// maxRangeBound is the largest magnitude that a range's bounds can have.
+// Beyond it, float64s can't represent every integer, so a range's values
+// wouldn't all be one apart.
+const maxRangeBound = 1 << 53
+
+func (i *Interpreter) rangeExpr(r *ast.Range) (*ast.Literal, error) {
+	start, err := i.evaluate(r.Start)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := i.evaluate(r.End)
+	if err != nil {
+		return nil, err
+	}
+
+	low, lowIsNumber := start.Value.(float64)
+	high, highIsNumber := end.Value.(float64)
+	if !lowIsNumber || !highIsNumber {
+		return nil, &Error{r.Operator, fmt.Sprintf("Range bounds must be numbers, got %s and %s.", typeName(start.Value), typeName(end.Value))}
+	}
+
+	for _, bound := range []float64{low, high} {
+		if !(math.Abs(bound) <= maxRangeBound) {
+			return nil, &Error{r.Operator, fmt.Sprintf("Range bounds must be between -2^53 and 2^53, got %s.", repr(bound))}
+		}
+	}
+
+	return &ast.Literal{Value: &LoxRange{
+		Start:     low,
+		End:       high,
+		Inclusive: r.Operator.Kind == token.KindDotDotEqual,
+	}}, nil
+}
+
+func (i *Interpreter) forInStmt(f *ast.ForInStatement) error {
+	iterable, err := i.evaluate(f.Iterable)
+	if err != nil {
+		return err
+	}
+
+	next, err := i.iterate(f.In, iterable)
+	if err != nil {
+		return err
+	}
+
+	for {
+		value, ok, err := next()
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return nil
+		}
+
+		// every iteration gets its own scope, so that closures created
+		// in the body capture that iteration's value
+		environment := env.New(i.env)
+		environment.Define(f.Name.Lexeme, &ast.Literal{Value: value})
+
+		err = i.executeBlock([]ast.Statement{f.Body}, environment)
+		if err != nil {
+			var brk *breakError
+			if errors.As(err, &brk) && targetsLoop(f.Label, brk.Label) {
+				return nil
+			}
+
+			var cont *continueError
+			if !errors.As(err, &cont) || !targetsLoop(f.Label, cont.Label) {
+				return err
+			}
+		}
+	}
+}
+
+// iterator returns the next value of a sequence each time that it's
+// called, and false once the sequence is exhausted.
+type iterator func() (value interface{}, ok bool, err error)
+
+// iterate returns an iterator over the values of "iterable":
+//
+//   - the characters of a string
+//   - the numbers in a range
+//   - the elements of a list or tuple, as of when the loop starts
+//   - the keys of a map, as of when the loop starts
+//   - the values returned by an instance's next() method, for as long
+//     as its hasNext() method returns a truthy value
+func (i *Interpreter) iterate(in token.Token, iterable *ast.Literal) (iterator, error) {
+	switch v := iterable.Value.(type) {
+	case string:
+		var characters []interface{}
+		for _, r := range v {
+			characters = append(characters, string(r))
+		}
+
+		return sliceIterator(characters), nil
+	case *LoxRange:
+		n, length := 0.0, v.length()
+		return func() (interface{}, bool, error) {
+			if n >= length {
+				return nil, false, nil
+			}
+
+			value := v.Start + n
+			n++
+			return value, true, nil
+		}, nil
+	case *LoxList:
+		return sliceIterator(append([]interface{}(nil), v.Elements...)), nil
+	case *LoxTuple:
+		return sliceIterator(v.Elements), nil
+	case *LoxMap:
+		return sliceIterator(v.Keys()), nil
+	case *LoxInstance:
+		hasNext, err := iteratorMethod(in, v, "hasNext")
+		if err != nil {
+			return nil, err
+		}
+
+		next, err := iteratorMethod(in, v, "next")
+		if err != nil {
+			return nil, err
+		}
+
+		return func() (interface{}, bool, error) {
+			more, err := i.callIteratorMethod(in, hasNext)
+			if err != nil || !isTruthy(more.Value) {
+				return nil, false, err
+			}
+
+			value, err := i.callIteratorMethod(in, next)
+			if err != nil {
+				return nil, false, err
+			}
+
+			return value.Value, true, nil
+		}, nil
+	}
+
+	return nil, &Error{in, fmt.Sprintf("Can only iterate over strings, ranges, lists, tuples, maps and iterators, got %s.", typeName(iterable.Value))}
+}
+
+func sliceIterator(values []interface{}) iterator {
+	n := 0
+	return func() (interface{}, bool, error) {
+		if n >= len(values) {
+			return nil, false, nil
+		}
+
+		value := values[n]
+		n++
+		return value, true, nil
+	}
+}
+
+// iteratorMethod looks up one of the methods of the iterator protocol on
+// "instance".
+func iteratorMethod(in token.Token, instance *LoxInstance, name string) (LoxCallable, error) {
+	property, err := instance.Get(token.Token{Kind: token.KindIdentifier, Lexeme: name, Line: in.Line})
+	if err != nil {
+		return nil, &Error{in, fmt.Sprintf("Iterators must have a %q method, but %s doesn't.", name, instance)}
+	}
+
+	method, ok := property.Value.(LoxCallable)
+	if !ok {
+		return nil, &Error{in, fmt.Sprintf("Iterators must have a %q method, but %s doesn't.", name, instance)}
+	}
+
+	return method, nil
+}
+
+// callIteratorMethod calls one of an iterator's methods, reporting errors
+// from natives (like arity mismatches) at the loop's "in".
+func (i *Interpreter) callIteratorMethod(in token.Token, method LoxCallable) (*ast.Literal, error) {
+	result, err := i.callFunction(method, nil)
+	if err != nil {
+		var nativeErr *nativeError
+		if errors.As(err, &nativeErr) {
+			return nil, &Error{in, nativeErr.Message}
+		}
+
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,77 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestForIn(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+for (c in "hé") record(c);
+for (n in 0..2) record(n);
+for (n in 1..=2) record(n);
+for (k in {"a": 1, "b": 2}) record(k);
+
+fun pair() { return "x", "y"; }
+for (v in pair()) record(v);
+
+var closures = [];
+for (n in [10, 20]) list.append(closures, fun() { return n; });
+for (f in closures) record(f());
+
+class Countdown {
+  init(n) { this.n = n; }
+  hasNext() { return this.n > 0; }
+  next() { this.n = this.n - 1; return this.n + 1; }
+}
+for (n in Countdown(2)) record(n);
+
+outer: for (i in 0..3) {
+  for (j in 0..3) {
+    if (j == 1) continue outer;
+    if (i == 2) break outer;
+    record([i, j]);
+  }
+}
+
+try { for (x in 5) {} } catch (e) { record(e.message); }
+try { for (x in "a".."b") {} } catch (e) { record(e.message); }
+try { for (x in 9007199254740992..9007199254740994) {} } catch (e) { record(e.message); }
+try { for (x in 0..1/0) {} } catch (e) { record(e.message); }
+for (x in 9007199254740990..=9007199254740992) record(x);
+class Empty {}
+try { for (x in Empty()) {} } catch (e) { record(e.message); }
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	list := func(elements ...interface{}) *LoxList {
+		return &LoxList{Elements: elements}
+	}
+
+	expected := []interface{}{
+		"h", "é",
+		0.0, 1.0,
+		1.0, 2.0,
+		"a", "b",
+		"x", "y",
+		10.0, 20.0,
+		2.0, 1.0,
+		list(0.0, 0.0),
+		list(1.0, 0.0),
+		"Can only iterate over strings, ranges, lists, tuples, maps and iterators, got number.",
+		"Range bounds must be numbers, got string and string.",
+		"Range bounds must be between -2^53 and 2^53, got 9007199254740994.",
+		"Range bounds must be between -2^53 and 2^53, got +Inf.",
+		9007199254740990.0, 9007199254740991.0, 9007199254740992.0,
+		`Iterators must have a "hasNext" method, but Empty instance doesn't.`,
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
@@ -0,0 +1,249 @@
+package interpreter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+)
+
+var jsonNatives = []*Native{
+	{Name: "jsonParse", Params: []Type{TypeString}, Fn: jsonParse},
+	{Name: "jsonStringify", Params: []Type{TypeAny, TypeNumber | TypeString | TypeNil}, Optional: 1, Fn: jsonStringify},
+}
+
+// jsonParse decodes a JSON document. Objects become maps (which keep the
+// order of their keys), arrays become lists, and null becomes nil.
+func jsonParse(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	input := arguments[0].Value.(string)
+
+	decoder := json.NewDecoder(strings.NewReader(input))
+	value, err := decodeJSON(decoder)
+	if err != nil {
+		offset := int64(len(input))
+
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			// the offset is just past the character that caused the error
+			offset = syntaxErr.Offset - 1
+		} else if errors.Is(err, io.EOF) {
+			err = errors.New("unexpected end of JSON input")
+		}
+
+		return nil, jsonParseError(input, offset, err)
+	}
+
+	// the document has to be a single value
+	rest := strings.TrimLeft(input[decoder.InputOffset():], " \t\r\n")
+	if rest != "" {
+		return nil, jsonParseError(input, int64(len(input)-len(rest)), errors.New("unexpected data after top-level value"))
+	}
+
+	return value, nil
+}
+
+// jsonParseError reports "err" at the line and column (both 1-based, with
+// columns counted in runes) of the byte offset in "input".
+func jsonParseError(input string, offset int64, err error) error {
+	before := input[:offset]
+
+	line := strings.Count(before, "\n") + 1
+	column := len([]rune(before[strings.LastIndex(before, "\n")+1:])) + 1
+
+	return nativeErrorf("jsonParse: %s at line %d, column %d.", err, line, column)
+}
+
+func decodeJSON(decoder *json.Decoder) (interface{}, error) {
+	t, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t {
+	case json.Delim('['):
+		list := &LoxList{}
+		for decoder.More() {
+			element, err := decodeJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			list.Elements = append(list.Elements, element)
+		}
+
+		// consume the ']'
+		_, err := decoder.Token()
+		return list, err
+	case json.Delim('{'):
+		m := NewMap()
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := decodeJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			m.Set(key, value)
+		}
+
+		// consume the '}'
+		_, err := decoder.Token()
+		return m, err
+	}
+
+	// everything else (strings, numbers, booleans and null) already
+	// decodes to the same values that glox uses
+	return t, nil
+}
+
+// maxJSONIndent is the largest number of spaces that jsonStringify will
+// indent by.
+const maxJSONIndent = 10
+
+// jsonStringify encodes a value as JSON. The optional second argument is
+// the indentation to use, either as a number of spaces or as a string; if
+// it's nil or missing, the JSON is written on a single line. Map keys
+// that aren't strings are converted to strings, the same way that they'd
+// be printed, and it's an error for two keys of a map to convert to the
+// same string.
+func jsonStringify(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	var b bytes.Buffer
+	err := encodeJSON(&b, arguments[0].Value, make(map[interface{}]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	var indent string
+	switch i := arguments[1].Value.(type) {
+	case string:
+		indent = i
+	case float64:
+		if i != math.Trunc(i) || i < 0 || i > maxJSONIndent {
+			return nil, nativeErrorf("jsonStringify: indent must be an integer from 0 to %d, got %s.", maxJSONIndent, repr(i))
+		}
+
+		indent = strings.Repeat(" ", int(i))
+	}
+
+	if indent == "" {
+		return b.String(), nil
+	}
+
+	var indented bytes.Buffer
+	err = json.Indent(&indented, b.Bytes(), "", indent)
+	if err != nil {
+		return nil, err
+	}
+
+	return indented.String(), nil
+}
+
+// encodeJSON writes "value" to "b" as compact JSON. "visiting" holds the
+// collections that are currently being encoded, so that collections that
+// contain themselves can be reported instead of recursing forever.
+func encodeJSON(b *bytes.Buffer, value interface{}, visiting map[interface{}]bool) error {
+	switch v := value.(type) {
+	case nil, bool, string:
+		return encodeJSONScalar(b, v)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nativeErrorf("jsonStringify: can't encode %s as JSON.", repr(v))
+		}
+
+		return encodeJSONScalar(b, v)
+	case *LoxList:
+		if visiting[v] {
+			return nativeErrorf("jsonStringify: can't encode a list that contains itself.")
+		}
+
+		visiting[v] = true
+		defer delete(visiting, v)
+
+		b.WriteByte('[')
+		for n, e := range v.Elements {
+			if n > 0 {
+				b.WriteByte(',')
+			}
+
+			err := encodeJSON(b, e, visiting)
+			if err != nil {
+				return err
+			}
+		}
+
+		b.WriteByte(']')
+		return nil
+	case *LoxTuple:
+		// tuples can't contain themselves, and are encoded as arrays
+		return encodeJSON(b, &LoxList{Elements: v.Elements}, visiting)
+	case *LoxMap:
+		if visiting[v] {
+			return nativeErrorf("jsonStringify: can't encode a map that contains itself.")
+		}
+
+		visiting[v] = true
+		defer delete(visiting, v)
+
+		// encoded maps each JSON key that's been written to the map key
+		// that it came from
+		encoded := make(map[string]interface{})
+
+		b.WriteByte('{')
+		for n, k := range v.keys {
+			if n > 0 {
+				b.WriteByte(',')
+			}
+
+			key, ok := k.(string)
+			if !ok {
+				key = (&ast.Literal{Value: k}).Output()
+			}
+
+			if other, found := encoded[key]; found {
+				return nativeErrorf("jsonStringify: map keys %s and %s both encode as %q.", repr(other), repr(k), key)
+			}
+
+			encoded[key] = k
+
+			err := encodeJSONScalar(b, key)
+			if err != nil {
+				return err
+			}
+
+			b.WriteByte(':')
+
+			err = encodeJSON(b, v.values[k], visiting)
+			if err != nil {
+				return err
+			}
+		}
+
+		b.WriteByte('}')
+		return nil
+	}
+
+	return nativeErrorf("jsonStringify: can't encode %s as JSON.", typeName(value))
+}
+
+func encodeJSONScalar(b *bytes.Buffer, value interface{}) error {
+	encoder := json.NewEncoder(b)
+	encoder.SetEscapeHTML(false)
+
+	err := encoder.Encode(value)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", repr(value), err)
+	}
+
+	// Encode always adds a newline after the value
+	b.Truncate(b.Len() - 1)
+	return nil
+}
@@ -0,0 +1,43 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJSON(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+var doc = "{\"b\": [1, null, \"é\"], \"a\": {\"ok\": true}}";
+record(map.keys(jsonParse(doc)));
+record(jsonStringify(jsonParse(doc)));
+record(jsonStringify([1], nil));
+record(jsonStringify({1: [], "x": "<&>"}, 1));
+try { jsonParse("{\n  \"a\": nope\n}"); } catch (e) { record(e.message); }
+try { jsonStringify([clock]); } catch (e) { record(e.message); }
+try { jsonStringify({1: 2, "1": 3}); } catch (e) { record(e.message); }
+try { jsonStringify([1], 11); } catch (e) { record(e.message); }
+try { jsonStringify([1], 1.5); } catch (e) { record(e.message); }
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	expected := []interface{}{
+		&LoxList{Elements: []interface{}{"b", "a"}},
+		`{"b":[1,null,"é"],"a":{"ok":true}}`,
+		"[1]",
+		"{\n \"1\": [],\n \"x\": \"<&>\"\n}",
+		"jsonParse: invalid character 'o' in literal null (expecting 'u') at line 2, column 9.",
+		"jsonStringify: can't encode function as JSON.",
+		`jsonStringify: map keys 1 and "1" both encode as "1".`,
+		"jsonStringify: indent must be an integer from 0 to 10, got 11.",
+		"jsonStringify: indent must be an integer from 0 to 10, got 1.5.",
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
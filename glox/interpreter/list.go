@@ -0,0 +1,250 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+	"github.com/ggilmore/bradfield-languages/glox/token"
+)
+
+// LoxList is the runtime representation of a list. Elements holds the
+// raw values of each element (the same values that ast.Literal wraps).
+type LoxList struct {
+	Elements []interface{}
+}
+
+// index validates that "rawIndex" is an integer that's within the bounds
+// of the list, and converts it to an int.
+func (l *LoxList) index(bracket token.Token, rawIndex interface{}) (int, error) {
+	return checkIndex(bracket, "List", rawIndex, len(l.Elements))
+}
+
+// checkIndex validates that "rawIndex" is an integer in [0, length), and
+// converts it to an int. "kind" names the indexed type in errors.
+func checkIndex(bracket token.Token, kind string, rawIndex interface{}, length int) (int, error) {
+	n, ok := rawIndex.(float64)
+	if !ok || n != math.Trunc(n) {
+		return 0, &Error{bracket, fmt.Sprintf("%s index must be an integer, got %s.", kind, repr(rawIndex))}
+	}
+
+	if n < 0 || int(n) >= length {
+		return 0, &Error{bracket, fmt.Sprintf("%s index %s out of range for %s of length %d.", kind, repr(n), strings.ToLower(kind), length)}
+	}
+
+	return int(n), nil
+}
+
+func (l *LoxList) String() string {
+	return l.format(make(map[interface{}]bool))
+}
+
+// format formats the list, printing it as "[...]" if it's already in
+// "visiting" (see formatRepr).
+func (l *LoxList) format(visiting map[interface{}]bool) string {
+	if visiting[l] {
+		return "[...]"
+	}
+
+	visiting[l] = true
+	defer delete(visiting, l)
+
+	var elements []string
+	for _, e := range l.Elements {
+		elements = append(elements, formatRepr(e, visiting))
+	}
+
+	return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
+}
+
+// repr formats "value" for display inside of a collection. It's the same
+// as ast.Literal.Output, except that strings are quoted so that they can
+// be told apart from other values.
+func repr(value interface{}) string {
+	return formatRepr(value, make(map[interface{}]bool))
+}
+
+// formatRepr is like repr, but "visiting" holds the collections that are
+// currently being formatted, so that collections that contain themselves
+// are printed with a placeholder instead of recursing forever.
+func formatRepr(value interface{}, visiting map[interface{}]bool) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case *LoxList:
+		return v.format(visiting)
+	case *LoxMap:
+		return v.format(visiting)
+	case *LoxTuple:
+		return v.format(visiting)
+	}
+
+	return (&ast.Literal{Value: value}).Output()
+}
+
+// listNatives are the members of the builtin "list" module.
+var listNatives = []*Native{
+	{Name: "length", Params: []Type{TypeList | TypeTuple}, Fn: listLength},
+	{Name: "append", Params: []Type{TypeList, TypeAny}, Fn: listAppend},
+	{Name: "slice", Params: []Type{TypeList, TypeNumber, TypeNumber}, Fn: listSlice},
+	{Name: "map", Params: []Type{TypeList, TypeFunction}, Fn: listMap},
+	{Name: "filter", Params: []Type{TypeList, TypeFunction}, Fn: listFilter},
+	{Name: "reduce", Params: []Type{TypeList, TypeFunction, TypeAny}, Fn: listReduce},
+	{Name: "sort", Params: []Type{TypeList, TypeFunction | TypeNil}, Optional: 1, Fn: listSort},
+}
+
+// listLength returns the number of elements in a list or tuple.
+func listLength(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	elements, _ := unpack(arguments[0].Value)
+	return float64(len(elements)), nil
+}
+
+// listAppend adds a value to the end of the list in place.
+func listAppend(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	list := arguments[0].Value.(*LoxList)
+
+	list.Elements = append(list.Elements, arguments[1].Value)
+	return nil, nil
+}
+
+// listSlice returns a new list containing the elements in [start, end).
+func listSlice(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	list := arguments[0].Value.(*LoxList)
+
+	start := arguments[1].Value.(float64)
+	end := arguments[2].Value.(float64)
+	if start != math.Trunc(start) || end != math.Trunc(end) {
+		return nil, nativeErrorf("slice: start and end must be integers.")
+	}
+
+	if start < 0 || end > float64(len(list.Elements)) || start > end {
+		return nil, nativeErrorf("slice: range [%s, %s) is out of bounds for list of length %d.", repr(start), repr(end), len(list.Elements))
+	}
+
+	elements := make([]interface{}, int(end)-int(start))
+	copy(elements, list.Elements[int(start):int(end)])
+
+	return &LoxList{Elements: elements}, nil
+}
+
+// listMap returns a new list containing the result of calling the
+// function on every element.
+func listMap(i *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	list := arguments[0].Value.(*LoxList)
+	function := arguments[1].Value.(LoxCallable)
+
+	out := &LoxList{}
+	for _, e := range list.Elements {
+		result, err := i.callFunction(function, []ast.Expression{&ast.Literal{Value: e}})
+		if err != nil {
+			return nil, err
+		}
+
+		out.Elements = append(out.Elements, result.Value)
+	}
+
+	return out, nil
+}
+
+// listFilter returns a new list containing the elements that the
+// function returned a truthy value for.
+func listFilter(i *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	list := arguments[0].Value.(*LoxList)
+	function := arguments[1].Value.(LoxCallable)
+
+	out := &LoxList{}
+	for _, e := range list.Elements {
+		result, err := i.callFunction(function, []ast.Expression{&ast.Literal{Value: e}})
+		if err != nil {
+			return nil, err
+		}
+
+		if isTruthy(result.Value) {
+			out.Elements = append(out.Elements, e)
+		}
+	}
+
+	return out, nil
+}
+
+// listReduce folds the list from left to right, calling the function
+// with the accumulator and each element in turn.
+func listReduce(i *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	list := arguments[0].Value.(*LoxList)
+	function := arguments[1].Value.(LoxCallable)
+
+	accumulator := arguments[2]
+	for _, e := range list.Elements {
+		var err error
+		accumulator, err = i.callFunction(function, []ast.Expression{accumulator, &ast.Literal{Value: e}})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return accumulator.Value, nil
+}
+
+// listSort returns a sorted copy of the list. The optional second argument
+// is a function that reports whether its first argument should come before
+// its second. If it's nil or missing, the list is sorted in ascending order, which
+// requires all of its elements to be numbers or all of them to be strings.
+func listSort(i *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	list := arguments[0].Value.(*LoxList)
+
+	elements := make([]interface{}, len(list.Elements))
+	copy(elements, list.Elements)
+
+	less := naturalLess
+	if arguments[1].Value != nil {
+		function := arguments[1].Value.(LoxCallable)
+		less = func(a, b interface{}) (bool, error) {
+			result, err := i.callFunction(function, []ast.Expression{&ast.Literal{Value: a}, &ast.Literal{Value: b}})
+			if err != nil {
+				return false, err
+			}
+
+			return isTruthy(result.Value), nil
+		}
+	}
+
+	// sort.SliceStable can't be interrupted, so remember the
+	// first error and skip any comparisons after it
+	var sortErr error
+	sort.SliceStable(elements, func(x, y int) bool {
+		if sortErr != nil {
+			return false
+		}
+
+		result, err := less(elements[x], elements[y])
+		if err != nil {
+			sortErr = err
+		}
+
+		return result
+	})
+
+	if sortErr != nil {
+		return nil, sortErr
+	}
+
+	return &LoxList{Elements: elements}, nil
+}
+
+func naturalLess(a, b interface{}) (bool, error) {
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			return x < y, nil
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return x < y, nil
+		}
+	}
+
+	return false, nativeErrorf("sort: can't compare %s and %s without a comparison function.", typeName(a), typeName(b))
+}
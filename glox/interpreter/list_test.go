@@ -0,0 +1,107 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLists(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+var xs = [3, 1, 2];
+xs[0] = 4;
+record(str(xs));
+record(xs[2]);
+record(list.length(xs));
+
+list.append(xs, 5);
+record(list.slice(xs, 1, 3));
+record(list.map(xs, fun (x) { return x * 10; }));
+record(list.filter(xs, fun (x) { return x > 2; }));
+record(list.reduce(xs, fun (sum, x) { return sum + x; }, 0));
+
+record(list.sort(xs));
+record(list.sort(xs, nil));
+record(list.sort(xs, fun (a, b) { return a > b; }));
+record(list.sort(["b", "a"]));
+
+try { xs[10]; } catch (e) { record(e.message); }
+try { xs[-1] = 0; } catch (e) { record(e.message); }
+try { xs[0.5]; } catch (e) { record(e.message); }
+try { xs["0"]; } catch (e) { record(e.message); }
+try { 1[0]; } catch (e) { record(e.message); }
+try { list.sort([1, "a"]); } catch (e) { record(e.message); }
+try { list.sort(); } catch (e) { record(e.message); }
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	list := func(elements ...interface{}) *LoxList {
+		return &LoxList{Elements: elements}
+	}
+
+	expected := []interface{}{
+		"[4, 1, 2]",
+		2.0,
+		3.0,
+		list(1.0, 2.0),
+		list(40.0, 10.0, 20.0, 50.0),
+		list(4.0, 5.0),
+		12.0,
+		list(1.0, 2.0, 4.0, 5.0),
+		list(1.0, 2.0, 4.0, 5.0),
+		list(5.0, 4.0, 2.0, 1.0),
+		list("a", "b"),
+		"List index 10 out of range for list of length 4.",
+		"List index -1 out of range for list of length 4.",
+		"List index must be an integer, got 0.5.",
+		`List index must be an integer, got "0".`,
+		"Only lists, tuples and maps can be indexed.",
+		"sort: can't compare string and number without a comparison function.",
+		"Expected 1 to 2 arguments but got 0.",
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestCyclicValues(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+var xs = [1];
+list.append(xs, xs);
+record(str(xs));
+record(str([xs, xs]));
+
+var m = {};
+m["self"] = m;
+m["list"] = [m];
+record(str(m));
+
+var ys = [];
+fun f() { return ys, 1; }
+list.append(ys, f());
+record(str(ys));
+record(str(f()));
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	expected := []interface{}{
+		"[1, [...]]",
+		"[[1, [...]], [1, [...]]]",
+		`{"self": {...}, "list": [{...}]}`,
+		"[([...], 1)]",
+		"([([...], 1)], 1)",
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
@@ -0,0 +1,151 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+)
+
+// LoxMap is the runtime representation of a map. Keys can be strings,
+// numbers or booleans, and iteration follows insertion order.
+type LoxMap struct {
+	keys   []interface{}
+	values map[interface{}]interface{}
+}
+
+func NewMap() *LoxMap {
+	return &LoxMap{
+		values: make(map[interface{}]interface{}),
+	}
+}
+
+// Get returns the value stored under "key". "found" is false if the map
+// doesn't contain the key.
+func (m *LoxMap) Get(key interface{}) (value interface{}, found bool) {
+	value, found = m.values[key]
+	return value, found
+}
+
+// Set stores "value" under "key". New keys are added to the end of the
+// map's iteration order, while existing keys keep their position.
+func (m *LoxMap) Set(key, value interface{}) {
+	if _, found := m.values[key]; !found {
+		m.keys = append(m.keys, key)
+	}
+
+	m.values[key] = value
+}
+
+// Delete removes "key" from the map, and reports whether it was present.
+func (m *LoxMap) Delete(key interface{}) bool {
+	if _, found := m.values[key]; !found {
+		return false
+	}
+
+	delete(m.values, key)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+// Keys returns a copy of the map's keys in insertion order.
+func (m *LoxMap) Keys() []interface{} {
+	keys := make([]interface{}, len(m.keys))
+	copy(keys, m.keys)
+
+	return keys
+}
+
+func (m *LoxMap) Len() int {
+	return len(m.keys)
+}
+
+func (m *LoxMap) String() string {
+	return m.format(make(map[interface{}]bool))
+}
+
+// format formats the map, printing it as "{...}" if it's already in
+// "visiting" (see formatRepr).
+func (m *LoxMap) format(visiting map[interface{}]bool) string {
+	if visiting[m] {
+		return "{...}"
+	}
+
+	visiting[m] = true
+	defer delete(visiting, m)
+
+	var entries []string
+	for _, k := range m.keys {
+		entries = append(entries, fmt.Sprintf("%s: %s", repr(k), formatRepr(m.values[k], visiting)))
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(entries, ", "))
+}
+
+// validMapKey returns an error message if "key" can't be used as a map key.
+func validMapKey(key interface{}) (string, bool) {
+	switch k := key.(type) {
+	case string, bool:
+		return "", true
+	case float64:
+		if math.IsNaN(k) {
+			return "NaN can't be used as a map key.", false
+		}
+
+		return "", true
+	}
+
+	return fmt.Sprintf("Map keys must be strings, numbers or booleans, got %s.", typeName(key)), false
+}
+
+// mapNatives are the members of the builtin "map" module.
+var mapNatives = []*Native{
+	{Name: "length", Params: []Type{TypeMap}, Fn: mapLength},
+	{Name: "keys", Params: []Type{TypeMap}, Fn: mapKeys},
+	{Name: "values", Params: []Type{TypeMap}, Fn: mapValues},
+	{Name: "has", Params: []Type{TypeMap, TypeAny}, Fn: mapHas},
+	{Name: "delete", Params: []Type{TypeMap, TypeAny}, Fn: mapDelete},
+}
+
+func mapLength(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	return float64(arguments[0].Value.(*LoxMap).Len()), nil
+}
+
+func mapKeys(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	m := arguments[0].Value.(*LoxMap)
+
+	return &LoxList{Elements: m.Keys()}, nil
+}
+
+func mapValues(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	m := arguments[0].Value.(*LoxMap)
+
+	values := &LoxList{}
+	for _, k := range m.keys {
+		values.Elements = append(values.Elements, m.values[k])
+	}
+
+	return values, nil
+}
+
+func mapHas(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	m := arguments[0].Value.(*LoxMap)
+
+	_, found := m.Get(arguments[1].Value)
+	return found, nil
+}
+
+// mapDelete removes a key from the map in place, and reports whether the
+// key was present.
+func mapDelete(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	m := arguments[0].Value.(*LoxMap)
+
+	return m.Delete(arguments[1].Value), nil
+}
@@ -0,0 +1,61 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMaps(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+var m = {"b": 1, 2: "two", true: nil};
+m["a"] = 3;
+m["b"] = 4;
+record(str(m));
+record(m["b"]);
+record(m["missing"]);
+
+record(map.has(m, true));
+record(map.has(m, "missing"));
+record(map.delete(m, 2));
+record(map.delete(m, 2));
+record(map.keys(m));
+record(map.values(m));
+record(map.length(m));
+
+// a "{" that starts a statement is a block, not a map
+{ var inBlock = {}; record(map.length(inBlock)); }
+
+try { m[[]] = 1; } catch (e) { record(e.message); }
+try { m[0/0] = 1; } catch (e) { record(e.message); }
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	list := func(elements ...interface{}) *LoxList {
+		return &LoxList{Elements: elements}
+	}
+
+	expected := []interface{}{
+		`{"b": 4, 2: "two", true: nil, "a": 3}`,
+		4.0,
+		nil,
+		true,
+		false,
+		true,
+		false,
+		list("b", true, "a"),
+		list(4.0, nil, 3.0),
+		3.0,
+		0.0,
+		"Map keys must be strings, numbers or booleans, got list.",
+		"NaN can't be used as a map key.",
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
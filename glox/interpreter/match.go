@@ -0,0 +1,62 @@
+package interpreter
+
+import "github.com/ggilmore/bradfield-languages/glox/ast"
+
+// patternMatches reports whether "value" matches the match pattern "p",
+// using the same notion of equality as "==".
+func patternMatches(p *ast.MatchPattern, value interface{}) bool {
+	if p.High == nil {
+		return isEqual(p.Value.Value, value)
+	}
+
+	return rangeContains(p, value)
+}
+
+// patternCovers reports whether every value that "b" matches is also
+// matched by "a".
+func patternCovers(a, b *ast.MatchPattern) bool {
+	if a.High == nil {
+		return b.High == nil && isEqual(a.Value.Value, b.Value.Value)
+	}
+
+	if b.High == nil {
+		return rangeContains(a, b.Value.Value)
+	}
+
+	aLow, aHigh := a.Value.Value.(float64), a.High.Value.(float64)
+	bLow, bHigh := b.Value.Value.(float64), b.High.Value.(float64)
+
+	if bLow < aLow {
+		return false
+	}
+
+	if a.Inclusive || !b.Inclusive {
+		return bHigh <= aHigh
+	}
+
+	return bHigh < aHigh
+}
+
+func rangeIsEmpty(p *ast.MatchPattern) bool {
+	low, high := p.Value.Value.(float64), p.High.Value.(float64)
+	if p.Inclusive {
+		return low > high
+	}
+
+	return low >= high
+}
+
+// rangeContains reports whether the range pattern "p" matches "value".
+func rangeContains(p *ast.MatchPattern, value interface{}) bool {
+	n, ok := value.(float64)
+	if !ok {
+		return false
+	}
+
+	low, high := p.Value.Value.(float64), p.High.Value.(float64)
+	if p.Inclusive {
+		return low <= n && n <= high
+	}
+
+	return low <= n && n < high
+}
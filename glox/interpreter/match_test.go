@@ -0,0 +1,109 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ggilmore/bradfield-languages/glox/parser"
+	"github.com/ggilmore/bradfield-languages/glox/scanner"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMatch(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+fun describe(x) {
+  match (x) {
+    case 0 => return "zero";
+    case "a", "b" => return "letter";
+    case nil, true => return "nil or true";
+    case -5..0 => return "negative";
+    case 1..=9 if x == 7 => return "lucky";
+    case 1..=9 => return "digit";
+    else => return "other";
+  }
+}
+
+for (x in [0, "b", nil, true, -3, 7, 9, 10, false, "c"]) record(describe(x));
+
+// without an else, a value that nothing matches runs no arm
+match (1) {
+  case 2 => record("unreachable");
+}
+record("done");
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	expected := []interface{}{
+		"zero",
+		"letter",
+		"nil or true",
+		"nil or true",
+		"negative",
+		"lucky",
+		"digit",
+		"other",
+		"other",
+		"other",
+		"done",
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestMatchWarnings(t *testing.T) {
+	input := `
+match (1) {
+  case 1, 1 => print 1;
+  case 0..10 if false => print 2;
+  case 5 => print 3;
+  case 0..=10 => print 4;
+  case 2..5 => print 5;
+  case 3..3 => print 6;
+  case "a" => print 7;
+  case "a" => print 8;
+}
+`
+
+	s, err := scanner.New(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to initialize scanner: %s", err)
+	}
+
+	tokens, err := s.Scan()
+	if err != nil {
+		t.Fatalf("while scanning input: %s", err)
+	}
+
+	statements, err := parser.NewParser(tokens).Parse()
+	if err != nil {
+		t.Fatalf("while parsing: %s", err)
+	}
+
+	r := NewResolver(New())
+	err = r.Resolve(statements)
+	if err != nil {
+		t.Fatalf("while resolving: %s", err)
+	}
+
+	var actual []string
+	for _, w := range r.Warnings() {
+		actual = append(actual, w.String())
+	}
+
+	expected := []string{
+		"[line 2] warning: Duplicate case 1.",
+		"[line 6] warning: Unreachable case 2..5, it's already covered by 0..=10.",
+		"[line 7] warning: Range 3..3 never matches anything.",
+		"[line 9] warning: Duplicate case a.",
+	}
+
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
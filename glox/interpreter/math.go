@@ -0,0 +1,72 @@
+package interpreter
+
+import (
+	"math"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+)
+
+// mathNatives are the members of the builtin "math" module.
+var mathNatives = []*Native{
+	numberFunction("sqrt", math.Sqrt),
+	numberFunction("floor", math.Floor),
+	numberFunction("ceil", math.Ceil),
+	numberFunction("abs", math.Abs),
+	numberFunction("sin", math.Sin),
+	numberFunction("cos", math.Cos),
+	numberFunction("tan", math.Tan),
+	numberFunction("log", math.Log),
+	{Name: "pow", Params: []Type{TypeNumber, TypeNumber}, Fn: mathPow},
+	{Name: "min", Params: []Type{TypeNumber, TypeNumber}, Variadic: true, Fn: mathMin},
+	{Name: "max", Params: []Type{TypeNumber, TypeNumber}, Variadic: true, Fn: mathMax},
+}
+
+var mathConstants = map[string]float64{
+	"pi":  math.Pi,
+	"e":   math.E,
+	"inf": math.Inf(1),
+}
+
+func mathModule() *LoxModule {
+	module := newNativeModule("math", mathNatives)
+	for name, value := range mathConstants {
+		module.globals.Define(name, &ast.Literal{Value: value})
+	}
+
+	return module
+}
+
+// numberFunction wraps a go function of one number as a native.
+func numberFunction(name string, fn func(float64) float64) *Native {
+	return &Native{
+		Name:   name,
+		Params: []Type{TypeNumber},
+		Fn: func(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+			return fn(arguments[0].Value.(float64)), nil
+		},
+	}
+}
+
+func mathPow(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	return math.Pow(arguments[0].Value.(float64), arguments[1].Value.(float64)), nil
+}
+
+// mathMin returns the smallest of one or more numbers.
+func mathMin(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	result := arguments[0].Value.(float64)
+	for _, a := range arguments[1:] {
+		result = math.Min(result, a.Value.(float64))
+	}
+
+	return result, nil
+}
+
+// mathMax returns the largest of one or more numbers.
+func mathMax(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	result := arguments[0].Value.(float64)
+	for _, a := range arguments[1:] {
+		result = math.Max(result, a.Value.(float64))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,194 @@
+package interpreter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+	"github.com/ggilmore/bradfield-languages/glox/env"
+	"github.com/ggilmore/bradfield-languages/glox/parser"
+	"github.com/ggilmore/bradfield-languages/glox/scanner"
+	"github.com/ggilmore/bradfield-languages/glox/token"
+)
+
+type Option func(i *Interpreter)
+
+// ScriptPath tells the interpreter which file the script it's running
+// was read from, so that its imports can be found relative to it.
+func ScriptPath(path string) Option {
+	return func(i *Interpreter) {
+		absolute, err := filepath.Abs(path)
+		if err != nil {
+			absolute = path
+		}
+
+		i.importing = append(i.importing, absolute)
+	}
+}
+
+// SearchPath adds directories that imports are looked for in if they
+// can't be found relative to the importing file.
+func SearchPath(dirs ...string) Option {
+	return func(i *Interpreter) {
+		i.searchPath = append(i.searchPath, dirs...)
+	}
+}
+
+// LoxModule is the namespace of an imported file. Its members are the
+// file's top-level definitions.
+type LoxModule struct {
+	Name    string
+	Path    string
+	globals *env.Environment
+}
+
+func (m *LoxModule) Get(name token.Token) (*ast.Literal, error) {
+	value, found := m.globals.GetAt(0, name.Lexeme)
+	if !found {
+		return nil, &Error{name, fmt.Sprintf("Module %q has no member %q.", m.Name, name.Lexeme)}
+	}
+
+	return value.(*ast.Literal), nil
+}
+
+func (m *LoxModule) String() string {
+	return fmt.Sprintf("<module %s>", m.Name)
+}
+
+func (i *Interpreter) importStmt(s *ast.ImportStatement) error {
+	err := i.checkRedeclaration(s.Name)
+	if err != nil {
+		return err
+	}
+
+	module, err := i.importModule(s)
+	if err != nil {
+		return err
+	}
+
+	i.env.Define(s.Name.Lexeme, &ast.Literal{Value: module})
+	return nil
+}
+
+// importModule runs the file that "s" imports in its own global scope,
+// the first time that it's imported. Later imports of the same file
+// share the module from the first one.
+func (i *Interpreter) importModule(s *ast.ImportStatement) (*LoxModule, error) {
+	path, err := i.findModule(s.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	module, found := i.modules[path]
+	if found {
+		return module, nil
+	}
+
+	for n, p := range i.importing {
+		if p == path {
+			var cycle []string
+			for _, c := range append(i.importing[n:], path) {
+				cycle = append(cycle, filepath.Base(c))
+			}
+
+			return nil, &Error{s.Path, fmt.Sprintf("Import cycle: %s.", strings.Join(cycle, " -> "))}
+		}
+	}
+
+	statements, err := parseModule(path)
+	if err != nil {
+		return nil, fmt.Errorf("importing %q: %w", path, err)
+	}
+
+	resolver := NewResolver(i)
+	err = resolver.Resolve(statements)
+
+	for _, w := range resolver.Warnings() {
+		fmt.Fprintln(os.Stderr, w)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("importing %q: %w", path, err)
+	}
+
+	base := filepath.Base(path)
+	module = &LoxModule{
+		Name:    strings.TrimSuffix(base, filepath.Ext(base)),
+		Path:    path,
+		globals: env.New(nil),
+	}
+
+	i.importing = append(i.importing, path)
+	err = i.executeBlock(statements, module.globals)
+	i.importing = i.importing[:len(i.importing)-1]
+
+	if err != nil {
+		return nil, err
+	}
+
+	i.modules[path] = module
+	return module, nil
+}
+
+// findModule returns the absolute path of the file that "pathToken"
+// refers to. Relative paths are looked up next to the importing file
+// first, and then in each directory of the search path.
+func (i *Interpreter) findModule(pathToken token.Token) (string, error) {
+	name := pathToken.Literal.(string)
+
+	candidates := []string{name}
+	if !filepath.IsAbs(name) {
+		dir := "."
+		if len(i.importing) > 0 {
+			dir = filepath.Dir(i.importing[len(i.importing)-1])
+		}
+
+		candidates = []string{filepath.Join(dir, name)}
+		for _, d := range i.searchPath {
+			candidates = append(candidates, filepath.Join(d, name))
+		}
+	}
+
+	for _, c := range candidates {
+		info, err := os.Stat(c)
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		absolute, err := filepath.Abs(c)
+		if err != nil {
+			return "", fmt.Errorf("finding module %q: %w", name, err)
+		}
+
+		return absolute, nil
+	}
+
+	return "", &Error{pathToken, fmt.Sprintf("Can't find module %q.", name)}
+}
+
+func parseModule(path string) ([]ast.Statement, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s, err := scanner.New(f)
+	if err != nil {
+		return nil, fmt.Errorf("intializing scanner: %w", err)
+	}
+
+	tokens, err := s.Scan()
+	if err != nil {
+		return nil, fmt.Errorf("scanning for tokens: %w", err)
+	}
+
+	statements, err := parser.NewParser(tokens).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("while parsing: %w", err)
+	}
+
+	return statements, nil
+}
@@ -0,0 +1,131 @@
+package interpreter
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestImports(t *testing.T) {
+	dir := t.TempDir()
+	lib := t.TempDir()
+
+	writeFiles(t, dir, map[string]string{
+		"counter.lox": `
+record("loading counter");
+var count = 0;
+fun increment() { count = count + 1; return count; }
+`,
+		"pkg/user.lox": `
+import "../counter.lox";
+import "shadowed.lox";
+var incremented = counter.increment();
+var shadowed = shadowed.name;
+`,
+		"pkg/shadowed.lox": `var name = "next to the importer";`,
+	})
+
+	writeFiles(t, lib, map[string]string{
+		"shadowed.lox": `var name = "search path";`,
+		"util.lox":     `var name = "util";`,
+	})
+
+	record, recorded := recorder()
+
+	err := run(t, `
+import "counter.lox";
+import "pkg/user.lox";
+import "util.lox" as helpers;
+import "shadowed.lox";
+
+// modules are only run once, and every import shares the same globals
+record(counter.increment());
+record(user.incremented);
+record(user.shadowed);
+record(helpers.name);
+record(shadowed.name);
+try { counter.missing; } catch (e) { record(e.message); }
+`, Natives(record), ScriptPath(filepath.Join(dir, "main.lox")), SearchPath(lib))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	expected := []interface{}{
+		"loading counter",
+		2.0,
+		1.0,
+		"next to the importer",
+		"util",
+		"search path",
+		`Module "counter" has no member "missing".`,
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, dir, map[string]string{
+		"a.lox": `import "b.lox";`,
+		"b.lox": `
+var x = 1;
+import "a.lox";
+`,
+	})
+
+	for _, tt := range []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "cycle",
+			input:    `import "a.lox";`,
+			expected: "[line 2] Import cycle: a.lox -> b.lox -> a.lox.",
+		},
+		{
+			name:     "missing",
+			input:    `import "missing.lox";`,
+			expected: `[line 0] Can't find module "missing.lox".`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := run(t, tt.input, ScriptPath(filepath.Join(dir, "main.lox")))
+
+			var runtimeErr *Error
+			if !errors.As(err, &runtimeErr) {
+				t.Fatalf("expected an interpreter.Error, got %v", err)
+			}
+
+			if runtimeErr.Error() != tt.expected {
+				t.Errorf("expected error %q, got %q", tt.expected, runtimeErr.Error())
+			}
+		})
+	}
+}
+
+// writeFiles creates each file in "files" (keyed by its path relative to
+// "dir"), along with any directories that it's in.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatalf("creating directory for %q: %s", name, err)
+		}
+
+		err = os.WriteFile(path, []byte(contents), 0644)
+		if err != nil {
+			t.Fatalf("writing %q: %s", name, err)
+		}
+	}
+}
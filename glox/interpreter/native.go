@@ -0,0 +1,241 @@
+package interpreter
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+	"github.com/ggilmore/bradfield-languages/glox/env"
+)
+
+// Type is a set of runtime types. Natives use it to describe the
+// arguments that they accept, e.g. TypeFunction | TypeNil.
+type Type uint
+
+const (
+	TypeNil Type = 1 << iota
+	TypeBool
+	TypeNumber
+	TypeString
+	TypeList
+	TypeMap
+	TypeFunction
+	TypeClass
+	TypeInstance
+	TypeModule
+	TypeRegex
+	TypeDate
+	TypeTuple
+	TypeRange
+
+	TypeAny = TypeNil | TypeBool | TypeNumber | TypeString | TypeList | TypeMap |
+		TypeFunction | TypeClass | TypeInstance | TypeModule | TypeRegex | TypeDate |
+		TypeTuple | TypeRange
+)
+
+var typeNames = []string{
+	"nil", "boolean", "number", "string", "list", "map",
+	"function", "class", "instance", "module", "regex", "date",
+	"tuple", "range",
+}
+
+// Accepts reports whether "value" (a raw runtime value) is one of the
+// types in the set. Classes are callable, so TypeFunction accepts them
+// too. TypeAny accepts every value, including ones of types that lox
+// doesn't know about, which embedders can define with Global.
+func (t Type) Accepts(value interface{}) bool {
+	if t == TypeAny {
+		return true
+	}
+
+	var kind Type
+	switch value.(type) {
+	case nil:
+		kind = TypeNil
+	case bool:
+		kind = TypeBool
+	case float64:
+		kind = TypeNumber
+	case string:
+		kind = TypeString
+	case *LoxList:
+		kind = TypeList
+	case *LoxMap:
+		kind = TypeMap
+	case *LoxClass:
+		kind = TypeClass | TypeFunction
+	case *LoxInstance:
+		kind = TypeInstance
+	case *LoxModule:
+		kind = TypeModule
+	case *LoxRegex:
+		kind = TypeRegex
+	case *LoxDate:
+		kind = TypeDate
+	case *LoxTuple:
+		kind = TypeTuple
+	case *LoxRange:
+		kind = TypeRange
+	case LoxCallable:
+		kind = TypeFunction
+	}
+
+	return t&kind != 0
+}
+
+func (t Type) String() string {
+	if t == TypeAny {
+		return "any value"
+	}
+
+	var names []string
+	for n, name := range typeNames {
+		if t&(1<<n) != 0 {
+			names = append(names, name)
+		}
+	}
+
+	return strings.Join(names, " or ")
+}
+
+// Native is a LoxCallable that's implemented in go, which embedders can
+// add to an interpreter with the Natives and NativeModule options.
+type Native struct {
+	Name string
+
+	// Params holds the type of each parameter. Arguments are checked
+	// against them before Fn is called.
+	Params []Type
+
+	// Variadic natives accept any number of arguments (including none)
+	// in place of their last parameter, all of which have its type. A
+	// variadic native without any parameters accepts any number of
+	// arguments of any type.
+	Variadic bool
+
+	// Optional is the number of trailing parameters (not counting a
+	// variadic one) that callers can leave out. Fn receives nil for each
+	// one that's missing.
+	Optional int
+
+	// Fn receives the already evaluated arguments, and returns the raw
+	// value of the result. Errors that don't come from the interpreter
+	// are reported as runtime errors at the location of the call.
+	Fn func(i *Interpreter, arguments []*ast.Literal) (interface{}, error)
+}
+
+func (n *Native) MinArity() int {
+	return n.fixedArity() - n.Optional
+}
+
+func (n *Native) MaxArity() int {
+	if n.Variadic {
+		return -1
+	}
+
+	return len(n.Params)
+}
+
+func (n *Native) Call(i *Interpreter, arguments []ast.Expression) (ast.Expression, error) {
+	var literals []*ast.Literal
+	for position, a := range arguments {
+		literal := a.(*ast.Literal)
+
+		if expected := n.paramType(position); !expected.Accepts(literal.Value) {
+			return nil, nativeErrorf("%s: expected %s for argument %d but got %s.", n.Name, expected, position+1, typeName(literal.Value))
+		}
+
+		literals = append(literals, literal)
+	}
+
+	for len(literals) < n.fixedArity() {
+		literals = append(literals, &ast.Literal{Value: nil})
+	}
+
+	value, err := n.Fn(i, literals)
+	if err != nil {
+		if !isInterpreterError(err) {
+			return nil, nativeErrorf("%s: %s", n.Name, err)
+		}
+
+		return nil, err
+	}
+
+	return &ast.Literal{Value: value}, nil
+}
+
+// fixedArity is the number of parameters that aren't variadic.
+func (n *Native) fixedArity() int {
+	if n.Variadic && len(n.Params) > 0 {
+		return len(n.Params) - 1
+	}
+
+	return len(n.Params)
+}
+
+// paramType returns the type of the parameter that the argument at
+// "position" is passed to.
+func (n *Native) paramType(position int) Type {
+	if len(n.Params) == 0 {
+		return TypeAny
+	}
+
+	if position >= len(n.Params) {
+		return n.Params[len(n.Params)-1]
+	}
+
+	return n.Params[position]
+}
+
+func (n *Native) String() string {
+	return "<native fn>"
+}
+
+// isInterpreterError reports whether "err" is one that the interpreter
+// knows how to report or unwind, as opposed to one from an embedder's go
+// code.
+func isInterpreterError(err error) bool {
+	var runtimeErr *Error
+	var nativeErr *nativeError
+	var thrown *thrownError
+	var halt Halt
+
+	return errors.As(err, &runtimeErr) || errors.As(err, &nativeErr) || errors.As(err, &thrown) || errors.As(err, &halt)
+}
+
+// Natives defines native functions as builtins, which every module can
+// see.
+func Natives(natives ...*Native) Option {
+	return func(i *Interpreter) {
+		for _, n := range natives {
+			i.builtins.Define(n.Name, &ast.Literal{Value: n})
+		}
+	}
+}
+
+// Global defines a builtin variable with the given raw value, e.g. a
+// float64 or a *LoxList.
+func Global(name string, value interface{}) Option {
+	return func(i *Interpreter) {
+		i.builtins.Define(name, &ast.Literal{Value: value})
+	}
+}
+
+// NativeModule defines a builtin module named "name", whose members are
+// the given native functions.
+func NativeModule(name string, natives ...*Native) Option {
+	return func(i *Interpreter) {
+		i.builtins.Define(name, &ast.Literal{Value: newNativeModule(name, natives)})
+	}
+}
+
+func newNativeModule(name string, natives []*Native) *LoxModule {
+	module := &LoxModule{Name: name, globals: env.New(nil)}
+	for _, n := range natives {
+		module.globals.Define(n.Name, &ast.Literal{Value: n})
+	}
+
+	return module
+}
+
+var _ LoxCallable = &Native{}
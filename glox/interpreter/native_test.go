@@ -0,0 +1,187 @@
+package interpreter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+	"github.com/ggilmore/bradfield-languages/glox/parser"
+	"github.com/ggilmore/bradfield-languages/glox/scanner"
+)
+
+func TestNatives(t *testing.T) {
+	record, recorded := recorder()
+
+	sum := &Native{
+		Name:     "sum",
+		Params:   []Type{TypeNumber},
+		Variadic: true,
+		Fn: func(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+			total := 0.0
+			for _, a := range arguments {
+				total += a.Value.(float64)
+			}
+
+			return total, nil
+		},
+	}
+
+	shout := &Native{
+		Name:   "shout",
+		Params: []Type{TypeString},
+		Fn: func(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+			s := arguments[0].Value.(string)
+			if s == "" {
+				return nil, errors.New("nothing to shout")
+			}
+
+			return strings.ToUpper(s), nil
+		},
+	}
+
+	// variadic natives without parameters take anything
+	count := &Native{
+		Name:     "count",
+		Variadic: true,
+		Fn: func(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+			return float64(len(arguments)), nil
+		},
+	}
+
+	err := run(t, `
+record(sum());
+record(sum(1, 2, 3));
+record(count());
+record(count(1, "2", nil));
+record(text.shout("hi"));
+record(handle);
+try { sum(1, "2"); } catch (e) { record(e.message); }
+try { text.shout(""); } catch (e) { record(e.message); }
+try { record(); } catch (e) { record(e.message); }
+`, Natives(record, sum, count), NativeModule("text", shout), Global("handle", handle("h")))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	expected := []interface{}{
+		0.0,
+		6.0,
+		0.0,
+		3.0,
+		"HI",
+		handle("h"),
+		"sum: expected number for argument 2 but got string.",
+		"shout: nothing to shout",
+		"Expected 1 arguments but got 0.",
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
+
+// handle is a value of a type that lox doesn't know about.
+type handle string
+
+func TestStringNatives(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+var s = "héllo wörld";
+record(string.length(s));
+record(string.substring(s, 1, 5));
+record(string.indexOf(s, "wö"));
+record(string.split("añb", ""));
+record(string.join([1, "b"], "-"));
+
+// the helpers live in modules, so their names are free for scripts
+var split = "mine";
+record(split);
+
+record(num(" 4.5 "));
+record(num("nope"));
+record(type(math.pi));
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	expected := []interface{}{
+		11.0,
+		"éllo",
+		6.0,
+		&LoxList{Elements: []interface{}{"a", "ñ", "b"}},
+		"1-b",
+		"mine",
+		4.5,
+		nil,
+		"number",
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestTypeString(t *testing.T) {
+	for _, tt := range []struct {
+		t        Type
+		expected string
+	}{
+		{TypeNumber, "number"},
+		{TypeFunction | TypeNil, "nil or function"},
+		{TypeAny, "any value"},
+	} {
+		if actual := tt.t.String(); actual != tt.expected {
+			t.Errorf("expected %q, got %q", tt.expected, actual)
+		}
+	}
+}
+
+// recorder returns a native function named "record" that appends its
+// argument to the returned slice.
+func recorder() (*Native, *[]interface{}) {
+	var recorded []interface{}
+	record := &Native{
+		Name:   "record",
+		Params: []Type{TypeAny},
+		Fn: func(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+			recorded = append(recorded, arguments[0].Value)
+			return nil, nil
+		},
+	}
+
+	return record, &recorded
+}
+
+// run scans, parses, resolves and interprets "source" with a new
+// interpreter.
+func run(t *testing.T, source string, options ...Option) error {
+	t.Helper()
+
+	s, err := scanner.New(strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("failed to initialize scanner: %s", err)
+	}
+
+	tokens, err := s.Scan()
+	if err != nil {
+		t.Fatalf("while scanning input: %s", err)
+	}
+
+	statements, err := parser.NewParser(tokens).Parse()
+	if err != nil {
+		t.Fatalf("while parsing: %s", err)
+	}
+
+	i := New(options...)
+	err = NewResolver(i).Resolve(statements)
+	if err != nil {
+		t.Fatalf("while resolving: %s", err)
+	}
+
+	return i.Interpret(statements)
+}
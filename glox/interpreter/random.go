@@ -0,0 +1,78 @@
+package interpreter
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+)
+
+// Seed seeds the interpreter's random number natives, so that scripts
+// that use them behave the same way every time. Without it, they're
+// seeded from the current time.
+func Seed(seed int64) Option {
+	return func(i *Interpreter) {
+		i.random = rand.New(rand.NewSource(seed))
+	}
+}
+
+var randomNatives = []*Native{
+	{Name: "random", Fn: random},
+	{Name: "randomInt", Params: []Type{TypeNumber, TypeNumber}, Fn: randomInt},
+	{Name: "shuffle", Params: []Type{TypeList}, Fn: shuffle},
+	{Name: "choice", Params: []Type{TypeList}, Fn: choice},
+}
+
+// random returns a number in [0, 1).
+func random(i *Interpreter, _ []*ast.Literal) (interface{}, error) {
+	return i.random.Float64(), nil
+}
+
+// randomInt returns an integer in [lo, hi], including both ends.
+func randomInt(i *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	lo := arguments[0].Value.(float64)
+	hi := arguments[1].Value.(float64)
+	if lo != math.Trunc(lo) || hi != math.Trunc(hi) {
+		return nil, nativeErrorf("randomInt: bounds must be integers.")
+	}
+
+	if math.IsInf(lo, 0) || math.IsInf(hi, 0) {
+		return nil, nativeErrorf("randomInt: bounds must be finite.")
+	}
+
+	if lo > hi {
+		return nil, nativeErrorf("randomInt: lower bound %s is greater than upper bound %s.", repr(lo), repr(hi))
+	}
+
+	// Int63n takes the number of possible results, which has to fit in
+	// an int64
+	if hi-lo >= math.MaxInt64 {
+		return nil, nativeErrorf("randomInt: range from %s to %s is too large.", repr(lo), repr(hi))
+	}
+
+	return lo + float64(i.random.Int63n(int64(hi-lo)+1)), nil
+}
+
+// shuffle returns a copy of the list in random order.
+func shuffle(i *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	list := arguments[0].Value.(*LoxList)
+
+	elements := make([]interface{}, len(list.Elements))
+	copy(elements, list.Elements)
+
+	i.random.Shuffle(len(elements), func(x, y int) {
+		elements[x], elements[y] = elements[y], elements[x]
+	})
+
+	return &LoxList{Elements: elements}, nil
+}
+
+// choice returns a random element of a non-empty list.
+func choice(i *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	list := arguments[0].Value.(*LoxList)
+	if len(list.Elements) == 0 {
+		return nil, nativeErrorf("choice: list is empty.")
+	}
+
+	return list.Elements[i.random.Intn(len(list.Elements))], nil
+}
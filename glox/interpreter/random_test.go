@@ -0,0 +1,73 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSeed(t *testing.T) {
+	script := `
+record(random());
+record(randomInt(1, 1000000));
+record(shuffle([1, 2, 3, 4, 5, 6, 7, 8]));
+record(choice(["a", "b", "c", "d"]));
+`
+
+	first, firstRecorded := recorder()
+	if err := run(t, script, Natives(first), Seed(7)); err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	second, secondRecorded := recorder()
+	if err := run(t, script, Natives(second), Seed(7)); err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	if diff := cmp.Diff(*firstRecorded, *secondRecorded); diff != "" {
+		t.Errorf("expected the same results from the same seed, got diff (-first +second):\n%s", diff)
+	}
+}
+
+func TestRandomIntBounds(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+for (var i = 0; i < 200; i = i + 1) {
+  record(randomInt(-2, 2));
+}
+
+try { randomInt(0, 1/0); } catch (e) { record(e.message); }
+try { randomInt(-1/0, 0); } catch (e) { record(e.message); }
+try { randomInt(0, 100000000000000000000); } catch (e) { record(e.message); }
+try { randomInt(-5000000000000000000, 5000000000000000000); } catch (e) { record(e.message); }
+try { randomInt(0.5, 1); } catch (e) { record(e.message); }
+try { randomInt(2, 1); } catch (e) { record(e.message); }
+`, Natives(record), Seed(1))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	seen := make(map[interface{}]bool)
+	for _, n := range (*recorded)[:200] {
+		seen[n] = true
+	}
+
+	expected := map[interface{}]bool{-2.0: true, -1.0: true, 0.0: true, 1.0: true, 2.0: true}
+	if diff := cmp.Diff(expected, seen); diff != "" {
+		t.Errorf("expected every integer in [-2, 2], got diff (-expected +actual):\n%s", diff)
+	}
+
+	expectedErrors := []interface{}{
+		"randomInt: bounds must be finite.",
+		"randomInt: bounds must be finite.",
+		"randomInt: range from 0 to 100000000000000000000 is too large.",
+		"randomInt: range from -5000000000000000000 to 5000000000000000000 is too large.",
+		"randomInt: bounds must be integers.",
+		"randomInt: lower bound 2 is greater than upper bound 1.",
+	}
+
+	if diff := cmp.Diff(expectedErrors, (*recorded)[200:]); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
@@ -0,0 +1,131 @@
+package interpreter
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+)
+
+// LoxRegex is a compiled regular expression, using the syntax of go's
+// regexp package.
+type LoxRegex struct {
+	regexp *regexp.Regexp
+}
+
+func (r *LoxRegex) String() string {
+	return fmt.Sprintf("<regex /%s/>", r.regexp)
+}
+
+// regexNatives are the members of the builtin "regex" module. Apart from
+// compile, they all accept either a compiled regex or a pattern string
+// as their first argument.
+var regexNatives = []*Native{
+	{Name: "compile", Params: []Type{TypeString}, Fn: regexCompile},
+	{Name: "matches", Params: []Type{TypeRegex | TypeString, TypeString}, Fn: regexMatches},
+	{Name: "findAll", Params: []Type{TypeRegex | TypeString, TypeString}, Fn: regexFindAll},
+	{Name: "captures", Params: []Type{TypeRegex | TypeString, TypeString}, Fn: regexCaptures},
+	{Name: "replace", Params: []Type{TypeRegex | TypeString, TypeString, TypeString}, Fn: regexReplace},
+	{Name: "split", Params: []Type{TypeRegex | TypeString, TypeString}, Fn: regexSplit},
+}
+
+func regexCompile(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	return regexArgument("compile", arguments[0])
+}
+
+// regexMatches reports whether the regex matches anywhere in the string.
+func regexMatches(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	r, err := regexArgument("matches", arguments[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return r.regexp.MatchString(arguments[1].Value.(string)), nil
+}
+
+// regexFindAll returns a list of every non-overlapping match in the
+// string.
+func regexFindAll(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	r, err := regexArgument("findAll", arguments[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return stringList(r.regexp.FindAllString(arguments[1].Value.(string), -1)), nil
+}
+
+// regexCaptures returns the first match in the string followed by the
+// text of each of its capture groups, or nil if there's no match. Groups
+// that didn't participate in the match are nil.
+func regexCaptures(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	r, err := regexArgument("captures", arguments[0])
+	if err != nil {
+		return nil, err
+	}
+
+	s := arguments[1].Value.(string)
+
+	indexes := r.regexp.FindStringSubmatchIndex(s)
+	if indexes == nil {
+		return nil, nil
+	}
+
+	captures := &LoxList{}
+	for n := 0; n < len(indexes); n += 2 {
+		var capture interface{}
+		if indexes[n] >= 0 {
+			capture = s[indexes[n]:indexes[n+1]]
+		}
+
+		captures.Elements = append(captures.Elements, capture)
+	}
+
+	return captures, nil
+}
+
+// regexReplace replaces every match in the string. The replacement can
+// refer to capture groups as $1 or ${name}.
+func regexReplace(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	r, err := regexArgument("replace", arguments[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return r.regexp.ReplaceAllString(arguments[1].Value.(string), arguments[2].Value.(string)), nil
+}
+
+// regexSplit splits the string around each match.
+func regexSplit(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	r, err := regexArgument("split", arguments[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return stringList(r.regexp.Split(arguments[1].Value.(string), -1)), nil
+}
+
+// regexArgument returns the compiled regex for an argument that's either
+// a regex or a pattern string.
+func regexArgument(native string, argument *ast.Literal) (*LoxRegex, error) {
+	if r, ok := argument.Value.(*LoxRegex); ok {
+		return r, nil
+	}
+
+	pattern := argument.Value.(string)
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, nativeErrorf("%s: invalid pattern %q: %s.", native, pattern, err)
+	}
+
+	return &LoxRegex{regexp: compiled}, nil
+}
+
+func stringList(strings []string) *LoxList {
+	list := &LoxList{}
+	for _, s := range strings {
+		list.Elements = append(list.Elements, s)
+	}
+
+	return list
+}
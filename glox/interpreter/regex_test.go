@@ -0,0 +1,39 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRegex(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+var date = regex.compile("(?P<year>\\d{4})-(\\d{2})");
+record(str(date));
+record(regex.matches(date, "on 2021-06"));
+record(regex.captures(date, "from 1999-12 to 2000-01"));
+record(regex.findAll(date, "1999-12 2000-01"));
+record(regex.replace(date, "2021-06", "$2/\${year}"));
+record(regex.split("\\s*;\\s*", "a ; b;c"));
+try { regex.compile("[a"); } catch (e) { record(e.line); }
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	expected := []interface{}{
+		`<regex /(?P<year>\d{4})-(\d{2})/>`,
+		true,
+		&LoxList{Elements: []interface{}{"1999-12", "1999", "12"}},
+		&LoxList{Elements: []interface{}{"1999-12", "2000-01"}},
+		"06/2021",
+		&LoxList{Elements: []interface{}{"a", "b", "c"}},
+		8.0,
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
@@ -7,9 +7,36 @@ import (
 	"github.com/ggilmore/bradfield-languages/glox/token"
 )
 
+type functionType int
+
+const (
+	functionTypeNone functionType = iota
+	functionTypeFunction
+	functionTypeInitializer
+	functionTypeMethod
+)
+
+type classType int
+
+const (
+	classTypeNone classType = iota
+	classTypeClass
+	classTypeSubclass
+)
+
 type Resolver struct {
 	interpreter *Interpreter
 	scopes      *stack
+
+	currentFunction functionType
+	currentClass    classType
+
+	warnings []*Warning
+
+	// loops holds the labels of the loops that enclose the code
+	// that's currently being resolved, innermost last. Unlabeled
+	// loops have an empty label.
+	loops []string
 }
 
 func NewResolver(interpreter *Interpreter) *Resolver {
@@ -30,12 +57,23 @@ func (r *Resolver) Resolve(statements []ast.Statement) error {
 	return nil
 }
 
+// Warnings returns the warnings that were found while resolving.
+func (r *Resolver) Warnings() []*Warning {
+	return r.warnings
+}
+
+func (r *Resolver) warn(t token.Token, message string) {
+	r.warnings = append(r.warnings, &Warning{t, message})
+}
+
 func (r *Resolver) resolveStatement(stmt ast.Statement) error {
 	switch s := stmt.(type) {
 	case *ast.BlockStatement:
 		return r.blockStmt(s)
 	case *ast.VarStatement:
 		return r.varStmt(s)
+	case *ast.DestructureStatement:
+		return r.destructureStmt(s)
 	case *ast.ExpressionStatement:
 		return r.expressionStatement(s)
 	case *ast.IfStatement:
@@ -48,6 +86,22 @@ func (r *Resolver) resolveStatement(stmt ast.Statement) error {
 		return r.returnStatement(s)
 	case *ast.WhileStatement:
 		return r.whileStatement(s)
+	case *ast.ForInStatement:
+		return r.forInStatement(s)
+	case *ast.ClassStatement:
+		return r.classStmt(s)
+	case *ast.BreakStatement:
+		return r.breakStatement(s)
+	case *ast.ContinueStatement:
+		return r.continueStatement(s)
+	case *ast.MatchStatement:
+		return r.matchStatement(s)
+	case *ast.ThrowStatement:
+		return r.throwStatement(s)
+	case *ast.TryStatement:
+		return r.tryStatement(s)
+	case *ast.ImportStatement:
+		return r.importStatement(s)
 	}
 
 	panic(fmt.Sprintf("unhandled statement type %+v", stmt))
@@ -62,19 +116,52 @@ func (r *Resolver) blockStmt(b *ast.BlockStatement) error {
 
 func (r *Resolver) varStmt(v *ast.VarStatement) error {
 	name := v.Name
-	r.scopes.Declare(name)
+	err := r.declare(name)
+	if err != nil {
+		return err
+	}
 
 	if v.Initializer != nil {
-		err := r.resolveExpression(v.Initializer)
+		err = r.resolveExpression(v.Initializer)
 		if err != nil {
 			return err
 		}
 	}
 
+	if v.Const {
+		r.scopes.DefineConstant(name)
+		return nil
+	}
+
 	r.scopes.Define(name)
 	return nil
 }
 
+func (r *Resolver) destructureStmt(d *ast.DestructureStatement) error {
+	for _, name := range d.Names {
+		err := r.declare(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := r.resolveExpression(d.Initializer)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range d.Names {
+		if d.Const {
+			r.scopes.DefineConstant(name)
+			continue
+		}
+
+		r.scopes.Define(name)
+	}
+
+	return nil
+}
+
 func (r *Resolver) expressionStatement(e *ast.ExpressionStatement) error {
 	return r.resolveExpression(e.Expression)
 }
@@ -103,7 +190,154 @@ func (r *Resolver) whileStatement(w *ast.WhileStatement) error {
 		return err
 	}
 
-	return r.resolveStatement(w.Body)
+	var label string
+	if w.Label != nil {
+		label = w.Label.Lexeme
+	}
+
+	r.loops = append(r.loops, label)
+	defer func() {
+		r.loops = r.loops[:len(r.loops)-1]
+	}()
+
+	err = r.resolveStatement(w.Body)
+	if err != nil {
+		return err
+	}
+
+	if w.Increment != nil {
+		return r.resolveExpression(w.Increment)
+	}
+
+	return nil
+}
+
+func (r *Resolver) matchStatement(m *ast.MatchStatement) error {
+	err := r.resolveExpression(m.Subject)
+	if err != nil {
+		return err
+	}
+
+	r.checkPatterns(m.Arms)
+
+	for _, arm := range m.Arms {
+		err := r.matchArm(arm)
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.Else != nil {
+		r.beginScope()
+		defer r.endScope()
+
+		return r.resolveStatement(m.Else)
+	}
+
+	return nil
+}
+
+func (r *Resolver) matchArm(arm *ast.MatchArm) error {
+	r.beginScope()
+	defer r.endScope()
+
+	if arm.Guard != nil {
+		err := r.resolveExpression(arm.Guard)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.resolveStatement(arm.Body)
+}
+
+// checkPatterns warns about patterns that can never match, because an
+// earlier arm without a guard already matches everything that they do.
+func (r *Resolver) checkPatterns(arms []*ast.MatchArm) {
+	var covering []*ast.MatchPattern
+
+	for _, arm := range arms {
+		for n, pattern := range arm.Patterns {
+			if pattern.High != nil && rangeIsEmpty(pattern) {
+				r.warn(pattern.Token, fmt.Sprintf("Range %s never matches anything.", pattern))
+				continue
+			}
+
+			// patterns earlier in the same arm share its guard,
+			// so they cover this one whether or not it has a guard
+			candidates := append(covering[:len(covering):len(covering)], arm.Patterns[:n]...)
+			for _, earlier := range candidates {
+				if patternCovers(earlier, pattern) {
+					message := fmt.Sprintf("Unreachable case %s, it's already covered by %s.", pattern, earlier)
+					if earlier.High == nil {
+						message = fmt.Sprintf("Duplicate case %s.", pattern)
+					}
+
+					r.warn(pattern.Token, message)
+					break
+				}
+			}
+		}
+
+		if arm.Guard == nil {
+			covering = append(covering, arm.Patterns...)
+		}
+	}
+}
+
+func (r *Resolver) forInStatement(f *ast.ForInStatement) error {
+	err := r.resolveExpression(f.Iterable)
+	if err != nil {
+		return err
+	}
+
+	var label string
+	if f.Label != nil {
+		label = f.Label.Lexeme
+	}
+
+	r.loops = append(r.loops, label)
+	defer func() {
+		r.loops = r.loops[:len(r.loops)-1]
+	}()
+
+	// the loop variable lives in its own scope, which the interpreter
+	// creates fresh for each iteration
+	r.beginScope()
+	defer r.endScope()
+
+	r.scopes.Declare(f.Name)
+	r.scopes.Define(f.Name)
+
+	return r.resolveStatement(f.Body)
+}
+
+func (r *Resolver) breakStatement(b *ast.BreakStatement) error {
+	return r.loopJump(b.Keyword, b.Label)
+}
+
+func (r *Resolver) continueStatement(c *ast.ContinueStatement) error {
+	return r.loopJump(c.Keyword, c.Label)
+}
+
+// loopJump checks that a break or continue statement is inside of a
+// loop, and that the loop it names (if any) encloses it.
+func (r *Resolver) loopJump(keyword token.Token, label *token.Token) error {
+	if len(r.loops) == 0 {
+		return &Error{keyword, fmt.Sprintf("Can't use '%s' outside of a loop.", keyword.Lexeme)}
+	}
+
+	if label == nil {
+		return nil
+	}
+
+	for _, l := range r.loops {
+		if l == label.Lexeme {
+			return nil
+		}
+	}
+
+	return &Error{*label, fmt.Sprintf("Undefined loop label %q.", label.Lexeme)}
 }
 
 func (r *Resolver) printStatement(p *ast.PrintStatement) error {
@@ -111,29 +345,157 @@ func (r *Resolver) printStatement(p *ast.PrintStatement) error {
 }
 
 func (r *Resolver) returnStatement(returnStmt *ast.ReturnStatement) error {
+	if r.currentFunction == functionTypeNone {
+		return &Error{returnStmt.Keyword, "Can't return from top-level code."}
+	}
+
 	if returnStmt.Value != nil {
+		if r.currentFunction == functionTypeInitializer {
+			return &Error{returnStmt.Keyword, "Can't return a value from an initializer."}
+		}
+
 		return r.resolveExpression(returnStmt.Value)
 	}
 
 	return nil
 }
 
+func (r *Resolver) throwStatement(t *ast.ThrowStatement) error {
+	return r.resolveExpression(t.Value)
+}
+
+func (r *Resolver) tryStatement(t *ast.TryStatement) error {
+	err := r.blockStmt(t.Body)
+	if err != nil {
+		return err
+	}
+
+	if t.Catch != nil {
+		// the exception is declared in the same scope as the
+		// catch block's statements
+		r.beginScope()
+		r.scopes.Declare(*t.CatchName)
+		r.scopes.Define(*t.CatchName)
+
+		err := r.Resolve(t.Catch.Statements)
+		r.endScope()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if t.Finally != nil {
+		return r.blockStmt(t.Finally)
+	}
+
+	return nil
+}
+
+// importStatement only allows imports at the top level of a file, so
+// that relative paths are always resolved against the file they're in.
+func (r *Resolver) importStatement(i *ast.ImportStatement) error {
+	if !r.scopes.isEmpty() {
+		return &Error{i.Keyword, "Can only import at the top level of a file."}
+	}
+
+	return nil
+}
+
 func (r *Resolver) functionStmt(f *ast.FunctionStatement) error {
-	r.scopes.Declare(f.Name)
+	err := r.declare(f.Name)
+	if err != nil {
+		return err
+	}
+
 	r.scopes.Define(f.Name)
 
-	return r.resolveFunction(f)
+	return r.resolveFunction(f, functionTypeFunction)
+}
+
+func (r *Resolver) classStmt(c *ast.ClassStatement) error {
+	enclosingClass := r.currentClass
+	r.currentClass = classTypeClass
+	defer func() {
+		r.currentClass = enclosingClass
+	}()
+
+	err := r.declare(c.Name)
+	if err != nil {
+		return err
+	}
+
+	r.scopes.Define(c.Name)
+
+	if c.Superclass != nil {
+		if c.Superclass.Identifier.Lexeme == c.Name.Lexeme {
+			return &Error{c.Superclass.Identifier, "A class can't inherit from itself."}
+		}
+
+		r.currentClass = classTypeSubclass
+
+		err := r.variable(c.Superclass)
+		if err != nil {
+			return err
+		}
+
+		r.beginScope()
+		defer r.endScope()
+
+		r.scopes.Define(token.Token{Kind: token.KindSuper, Lexeme: "super"})
+	}
+
+	r.beginScope()
+	defer r.endScope()
+
+	r.scopes.Define(token.Token{Kind: token.KindThis, Lexeme: "this"})
+
+	for _, method := range c.Methods {
+		declaration := functionTypeMethod
+		if method.Name.Lexeme == "init" {
+			declaration = functionTypeInitializer
+		}
+
+		err := r.resolveFunction(method, declaration)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (r *Resolver) resolveFunction(f *ast.FunctionStatement) error {
+func (r *Resolver) resolveFunction(f *ast.FunctionStatement, kind functionType) error {
+	enclosingFunction := r.currentFunction
+	enclosingLoops := r.loops
+	r.currentFunction = kind
+	r.loops = nil
+	defer func() {
+		r.currentFunction = enclosingFunction
+		r.loops = enclosingLoops
+	}()
+
 	r.beginScope()
 	defer r.endScope()
 
-	for _, p := range f.Params {
+	for n, p := range f.Params {
+		// default values can refer to the parameters before them
+		if n < len(f.Defaults) && f.Defaults[n] != nil {
+			err := r.resolveExpression(f.Defaults[n])
+			if err != nil {
+				return err
+			}
+		}
+
 		r.scopes.Declare(p)
 		r.scopes.Define(p)
 	}
 
+	if f.Rest != nil {
+		r.scopes.Declare(*f.Rest)
+		r.scopes.Define(*f.Rest)
+	}
+
 	return r.Resolve(f.Body)
 }
 
@@ -157,6 +519,38 @@ func (r *Resolver) resolveExpression(expr ast.Expression) error {
 		return r.unary(e)
 	case *ast.Debug:
 		return r.debug(e)
+	case *ast.Get:
+		return r.get(e)
+	case *ast.Set:
+		return r.set(e)
+	case *ast.This:
+		return r.this(e)
+	case *ast.Super:
+		return r.super(e)
+	case *ast.Lambda:
+		return r.lambda(e)
+	case *ast.List:
+		return r.list(e)
+	case *ast.Index:
+		return r.index(e)
+	case *ast.IndexSet:
+		return r.indexSet(e)
+	case *ast.Map:
+		return r.mapLiteral(e)
+	case *ast.Stringify:
+		return r.stringify(e)
+	case *ast.CompoundAssignment:
+		return r.compoundAssignment(e)
+	case *ast.Update:
+		return r.update(e)
+	case *ast.Conditional:
+		return r.conditional(e)
+	case *ast.Tuple:
+		return r.tuple(e)
+	case *ast.Destructure:
+		return r.destructure(e)
+	case *ast.Range:
+		return r.rangeExpr(e)
 	}
 
 	panic(fmt.Sprintf("unhandled expression type %+v", expr))
@@ -174,8 +568,8 @@ func (r *Resolver) variable(v *ast.Variable) error {
 			return &Error{v.Identifier, "scope is empty"}
 		}
 
-		defined, found := scope[name]
-		if found && !defined {
+		b, found := scope[name]
+		if found && !b.defined {
 			return &Error{v.Identifier, "Can't read local variable inside its own initializer"}
 		}
 	}
@@ -208,6 +602,10 @@ func (r *Resolver) call(c *ast.Call) error {
 	}
 
 	for _, a := range c.Arguments {
+		if spread, ok := a.(*ast.Spread); ok {
+			a = spread.Value
+		}
+
 		err = r.resolveExpression(a)
 		if err != nil {
 			return err
@@ -217,6 +615,98 @@ func (r *Resolver) call(c *ast.Call) error {
 	return nil
 }
 
+func (r *Resolver) get(g *ast.Get) error {
+	return r.resolveExpression(g.Object)
+}
+
+func (r *Resolver) set(s *ast.Set) error {
+	err := r.resolveExpression(s.Value)
+	if err != nil {
+		return err
+	}
+
+	return r.resolveExpression(s.Object)
+}
+
+func (r *Resolver) this(t *ast.This) error {
+	if r.currentClass == classTypeNone {
+		return &Error{t.Keyword, "Can't use 'this' outside of a class."}
+	}
+
+	r.local(t, t.Keyword)
+	return nil
+}
+
+func (r *Resolver) super(s *ast.Super) error {
+	switch r.currentClass {
+	case classTypeNone:
+		return &Error{s.Keyword, "Can't use 'super' outside of a class."}
+	case classTypeClass:
+		return &Error{s.Keyword, "Can't use 'super' in a class with no superclass."}
+	}
+
+	r.local(s, s.Keyword)
+	return nil
+}
+
+func (r *Resolver) lambda(l *ast.Lambda) error {
+	return r.resolveFunction(l.Declaration, functionTypeFunction)
+}
+
+func (r *Resolver) list(l *ast.List) error {
+	for _, e := range l.Elements {
+		err := r.resolveExpression(e)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Resolver) index(i *ast.Index) error {
+	err := r.resolveExpression(i.Object)
+	if err != nil {
+		return err
+	}
+
+	return r.resolveExpression(i.Index)
+}
+
+func (r *Resolver) indexSet(i *ast.IndexSet) error {
+	err := r.resolveExpression(i.Object)
+	if err != nil {
+		return err
+	}
+
+	err = r.resolveExpression(i.Index)
+	if err != nil {
+		return err
+	}
+
+	return r.resolveExpression(i.Value)
+}
+
+func (r *Resolver) mapLiteral(m *ast.Map) error {
+	for i := range m.Keys {
+		err := r.resolveExpression(m.Keys[i])
+		if err != nil {
+			return err
+		}
+
+		err = r.resolveExpression(m.Values[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Resolver) stringify(s *ast.Stringify) error {
+	return r.resolveExpression(s.Expression)
+}
+
 func (r *Resolver) logical(l *ast.Logical) error {
 	err := r.resolveExpression(l.Left)
 	if err != nil {
@@ -226,6 +716,20 @@ func (r *Resolver) logical(l *ast.Logical) error {
 	return r.resolveExpression(l.Right)
 }
 
+func (r *Resolver) conditional(c *ast.Conditional) error {
+	err := r.resolveExpression(c.Condition)
+	if err != nil {
+		return err
+	}
+
+	err = r.resolveExpression(c.Then)
+	if err != nil {
+		return err
+	}
+
+	return r.resolveExpression(c.Else)
+}
+
 func (r *Resolver) unary(u *ast.Unary) error {
 	return r.resolveExpression(u.Right)
 }
@@ -236,10 +740,120 @@ func (r *Resolver) assignment(a *ast.Assignment) error {
 		return err
 	}
 
+	err = r.checkAssignable(a.Name)
+	if err != nil {
+		return err
+	}
+
 	r.local(a, a.Name)
 	return nil
 }
 
+// declare declares "name" in the innermost scope, unless it's already a
+// constant there. Like checkAssignable, it leaves global constants for the
+// interpreter to check.
+func (r *Resolver) declare(name token.Token) error {
+	if scope, ok := r.scopes.Peek(); ok {
+		if b, found := scope[name.Lexeme]; found && b.constant {
+			return &Error{name, fmt.Sprintf("Can't redeclare constant %q.", name.Lexeme)}
+		}
+	}
+
+	r.scopes.Declare(name)
+	return nil
+}
+
+// checkAssignable reports an error if "name" refers to a local constant.
+// Global constants are checked by the interpreter instead, since globals
+// can be defined after the code that uses them is resolved.
+func (r *Resolver) checkAssignable(name token.Token) error {
+	for i := r.scopes.Size() - 1; i >= 0; i-- {
+		scope, _ := r.scopes.Get(i)
+		b, found := scope[name.Lexeme]
+		if !found {
+			continue
+		}
+
+		if b.constant {
+			return &Error{name, fmt.Sprintf("Can't assign to constant %q.", name.Lexeme)}
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+func (r *Resolver) compoundAssignment(c *ast.CompoundAssignment) error {
+	err := r.resolveExpression(c.Value)
+	if err != nil {
+		return err
+	}
+
+	if v, ok := c.Target.(*ast.Variable); ok {
+		err := r.checkAssignable(v.Identifier)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.resolveExpression(c.Target)
+}
+
+func (r *Resolver) update(u *ast.Update) error {
+	if v, ok := u.Target.(*ast.Variable); ok {
+		err := r.checkAssignable(v.Identifier)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.resolveExpression(u.Target)
+}
+
+func (r *Resolver) rangeExpr(e *ast.Range) error {
+	err := r.resolveExpression(e.Start)
+	if err != nil {
+		return err
+	}
+
+	return r.resolveExpression(e.End)
+}
+
+func (r *Resolver) tuple(t *ast.Tuple) error {
+	for _, e := range t.Elements {
+		err := r.resolveExpression(e)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Resolver) destructure(d *ast.Destructure) error {
+	err := r.resolveExpression(d.Value)
+	if err != nil {
+		return err
+	}
+
+	for _, target := range d.Targets {
+		if v, ok := target.(*ast.Variable); ok {
+			err := r.checkAssignable(v.Identifier)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := r.resolveExpression(target)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (r *Resolver) local(e ast.Expression, name token.Token) {
 	for i := r.scopes.Size() - 1; i >= 0; i-- {
 		scope, _ := r.scopes.Get(i)
@@ -298,20 +912,24 @@ func (s *stack) Declare(name token.Token) {
 		return
 	}
 
-	scope[name.Lexeme] = false
+	scope[name.Lexeme] = &binding{}
 }
 
 func (s *stack) Define(name token.Token) {
-	if s.isEmpty() {
-		return
-	}
+	s.define(name, false)
+}
 
+func (s *stack) DefineConstant(name token.Token) {
+	s.define(name, true)
+}
+
+func (s *stack) define(name token.Token, constant bool) {
 	scope, ok := s.Peek()
 	if !ok {
 		return
 	}
 
-	scope[name.Lexeme] = true
+	scope[name.Lexeme] = &binding{defined: true, constant: constant}
 }
 
 func (s *stack) Size() int {
@@ -330,7 +948,13 @@ func (s *stack) isEmpty() bool {
 	return len(s.data) == 0
 }
 
-type scope map[string]bool
+// binding is what the resolver knows about a variable in a scope.
+type binding struct {
+	defined  bool
+	constant bool
+}
+
+type scope map[string]*binding
 
 func newScope() scope {
 	return make(scope)
@@ -0,0 +1,188 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ggilmore/bradfield-languages/glox/parser"
+	"github.com/ggilmore/bradfield-languages/glox/scanner"
+)
+
+func TestResolverConstants(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "assignment",
+			input:    "{ const a = 1; a = 2; }",
+			expected: `[line 0] Can't assign to constant "a".`,
+		},
+		{
+			name:     "compound assignment from a closure",
+			input:    "fun f() { const a = 1; fun g() { a += 1; } }",
+			expected: `[line 0] Can't assign to constant "a".`,
+		},
+		{
+			name:     "increment",
+			input:    "{ const a = 1; a++; }",
+			expected: `[line 0] Can't assign to constant "a".`,
+		},
+		{
+			name:     "destructuring assignment",
+			input:    "{ const [a, b] = [1, 2]; [b, a] = [a, b]; }",
+			expected: `[line 0] Can't assign to constant "b".`,
+		},
+		{
+			name:     "destructured name in its own initializer",
+			input:    "{ var [a, b] = [1, a]; }",
+			expected: "[line 0] Can't read local variable inside its own initializer",
+		},
+		{
+			name:     "redeclared as a variable",
+			input:    "{ const a = 1; var a = 2; a = 3; }",
+			expected: `[line 0] Can't redeclare constant "a".`,
+		},
+		{
+			name:     "redeclared by destructuring",
+			input:    "{ const a = 1; var [a, b] = [7, 2]; a = 9; }",
+			expected: `[line 0] Can't redeclare constant "a".`,
+		},
+		{
+			name:     "redeclared as a function",
+			input:    "fun f() { const a = 1; fun a() {} }",
+			expected: `[line 0] Can't redeclare constant "a".`,
+		},
+		{
+			name:  "shadowed by a variable",
+			input: "{ const a = 1; { var a = 2; a = 3; } }",
+		},
+		{
+			// globals are checked when the assignment runs
+			name:  "global",
+			input: "const a = 1; a = 2;",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := resolveError(t, tt.input); actual != tt.expected {
+				t.Errorf("expected error %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestResolverClasses(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "inheriting from itself",
+			input:    "class A < A {}",
+			expected: "[line 0] A class can't inherit from itself.",
+		},
+		{
+			name:     "super without a superclass",
+			input:    "class A { f() { super.f(); } }",
+			expected: "[line 0] Can't use 'super' in a class with no superclass.",
+		},
+		{
+			name:     "super outside of a class",
+			input:    "fun f() { super.f(); }",
+			expected: "[line 0] Can't use 'super' outside of a class.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := resolveError(t, tt.input); actual != tt.expected {
+				t.Errorf("expected error %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestResolverLoops(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "break outside of a loop",
+			input:    "break;",
+			expected: "[line 0] Can't use 'break' outside of a loop.",
+		},
+		{
+			name:     "continue in a function inside of a loop",
+			input:    "while (true) { fun f() { continue; } }",
+			expected: "[line 0] Can't use 'continue' outside of a loop.",
+		},
+		{
+			name:     "undefined label",
+			input:    "outer: while (true) { break inner; }",
+			expected: `[line 0] Undefined loop label "inner".`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := resolveError(t, tt.input); actual != tt.expected {
+				t.Errorf("expected error %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+// resolveError parses "input" and returns the message of the error that
+// resolving it reports, or "" if there isn't one.
+func resolveError(t *testing.T, input string) string {
+	t.Helper()
+
+	s, err := scanner.New(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to initialize scanner: %s", err)
+	}
+
+	tokens, err := s.Scan()
+	if err != nil {
+		t.Fatalf("while scanning input: %s", err)
+	}
+
+	statements, err := parser.NewParser(tokens).Parse()
+	if err != nil {
+		t.Fatalf("while parsing: %s", err)
+	}
+
+	if err := NewResolver(New()).Resolve(statements); err != nil {
+		return err.Error()
+	}
+
+	return ""
+}
+
+func TestGlobalConstants(t *testing.T) {
+	err := run(t, `
+const limit = 1;
+fun raise() { limit = 2; }
+raise();
+`)
+
+	expected := `[line 2] Can't assign to constant "limit".`
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestGlobalConstantRedeclaration(t *testing.T) {
+	for _, input := range []string{
+		"const a = 1; var a = 2; a = 3;",
+		"const a = 1; const [a] = [2];",
+		"const a = 1; class a {}",
+	} {
+		err := run(t, input)
+
+		expected := `[line 0] Can't redeclare constant "a".`
+		if err == nil || err.Error() != expected {
+			t.Errorf("%s: expected error %q, got %v", input, expected, err)
+		}
+	}
+}
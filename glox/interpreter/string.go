@@ -0,0 +1,101 @@
+package interpreter
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+)
+
+// stringNatives are the members of the builtin "string" module. They
+// index strings by rune rather than by byte, the same way that the
+// scanner reads its input.
+var stringNatives = []*Native{
+	{Name: "length", Params: []Type{TypeString}, Fn: stringLength},
+	{Name: "substring", Params: []Type{TypeString, TypeNumber, TypeNumber}, Fn: stringSubstring},
+	{Name: "indexOf", Params: []Type{TypeString, TypeString}, Fn: stringIndexOf},
+	{Name: "split", Params: []Type{TypeString, TypeString}, Fn: stringSplit},
+	{Name: "join", Params: []Type{TypeList, TypeString}, Fn: stringJoin},
+	{Name: "upper", Params: []Type{TypeString}, Fn: stringUpper},
+	{Name: "lower", Params: []Type{TypeString}, Fn: stringLower},
+	{Name: "trim", Params: []Type{TypeString}, Fn: stringTrim},
+	{Name: "replace", Params: []Type{TypeString, TypeString, TypeString}, Fn: stringReplace},
+}
+
+// stringLength returns the number of runes in the string.
+func stringLength(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	return float64(utf8.RuneCountInString(arguments[0].Value.(string))), nil
+}
+
+// stringSubstring returns the runes in [start, end).
+func stringSubstring(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	runes := []rune(arguments[0].Value.(string))
+
+	start := arguments[1].Value.(float64)
+	end := arguments[2].Value.(float64)
+	if start != math.Trunc(start) || end != math.Trunc(end) {
+		return nil, nativeErrorf("substring: start and end must be integers.")
+	}
+
+	if start < 0 || end > float64(len(runes)) || start > end {
+		return nil, nativeErrorf("substring: range [%s, %s) is out of bounds for string of length %d.", repr(start), repr(end), len(runes))
+	}
+
+	return string(runes[int(start):int(end)]), nil
+}
+
+// stringIndexOf returns the index of the first occurrence of the second
+// string in the first, or -1 if it doesn't occur.
+func stringIndexOf(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	s := arguments[0].Value.(string)
+
+	index := strings.Index(s, arguments[1].Value.(string))
+	if index < 0 {
+		return -1.0, nil
+	}
+
+	return float64(utf8.RuneCountInString(s[:index])), nil
+}
+
+// stringSplit splits the string around each occurrence of the separator.
+// An empty separator splits the string into its runes.
+func stringSplit(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	parts := &LoxList{}
+	for _, p := range strings.Split(arguments[0].Value.(string), arguments[1].Value.(string)) {
+		parts.Elements = append(parts.Elements, p)
+	}
+
+	return parts, nil
+}
+
+// stringJoin joins the list's elements with the separator. Elements that
+// aren't strings are joined the same way that they'd be printed.
+func stringJoin(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	var parts []string
+	for _, e := range arguments[0].Value.(*LoxList).Elements {
+		parts = append(parts, (&ast.Literal{Value: e}).Output())
+	}
+
+	return strings.Join(parts, arguments[1].Value.(string)), nil
+}
+
+func stringUpper(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	return strings.ToUpper(arguments[0].Value.(string)), nil
+}
+
+func stringLower(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	return strings.ToLower(arguments[0].Value.(string)), nil
+}
+
+// stringTrim removes leading and trailing whitespace.
+func stringTrim(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	return strings.TrimSpace(arguments[0].Value.(string)), nil
+}
+
+// stringReplace replaces every occurrence of the second string with the
+// third.
+func stringReplace(_ *Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	s := arguments[0].Value.(string)
+	return strings.ReplaceAll(s, arguments[1].Value.(string), arguments[2].Value.(string)), nil
+}
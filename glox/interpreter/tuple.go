@@ -0,0 +1,147 @@
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+	"github.com/ggilmore/bradfield-languages/glox/token"
+)
+
+// LoxTuple is the runtime representation of the values returned by
+// "return a, b;". Unlike lists, tuples can't be modified, and two tuples
+// are equal if their elements are.
+type LoxTuple struct {
+	Elements []interface{}
+}
+
+func (t *LoxTuple) String() string {
+	return t.format(make(map[interface{}]bool))
+}
+
+// format formats the tuple. Tuples can't contain themselves directly,
+// but their elements can contain the tuple, so "visiting" (see
+// formatRepr) is passed on to them.
+func (t *LoxTuple) format(visiting map[interface{}]bool) string {
+	var elements []string
+	for _, e := range t.Elements {
+		elements = append(elements, formatRepr(e, visiting))
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(elements, ", "))
+}
+
+// equal reports whether both tuples have the same number of elements, and
+// each pair of elements is equal.
+func (t *LoxTuple) equal(other *LoxTuple) bool {
+	if len(t.Elements) != len(other.Elements) {
+		return false
+	}
+
+	for n, e := range t.Elements {
+		if !isEqual(e, other.Elements[n]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// unpack returns the elements of "value" if it's a list or a tuple, the
+// two kinds of values that can be destructured or spread.
+func unpack(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case *LoxList:
+		return v.Elements, true
+	case *LoxTuple:
+		return v.Elements, true
+	}
+
+	return nil, false
+}
+
+func (i *Interpreter) tuple(t *ast.Tuple) (*ast.Literal, error) {
+	tuple := &LoxTuple{}
+	for _, rawElement := range t.Elements {
+		element, err := i.evaluate(rawElement)
+		if err != nil {
+			return nil, err
+		}
+
+		tuple.Elements = append(tuple.Elements, element.Value)
+	}
+
+	return &ast.Literal{Value: tuple}, nil
+}
+
+func (i *Interpreter) destructureStmt(d *ast.DestructureStatement) error {
+	for _, name := range d.Names {
+		err := i.checkRedeclaration(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	value, err := i.evaluate(d.Initializer)
+	if err != nil {
+		return err
+	}
+
+	elements, err := destructure(d.Bracket, value, len(d.Names))
+	if err != nil {
+		return err
+	}
+
+	for n, name := range d.Names {
+		element := &ast.Literal{Value: elements[n]}
+		if d.Const {
+			i.env.DefineConstant(name.Lexeme, element)
+			continue
+		}
+
+		i.env.Define(name.Lexeme, element)
+	}
+
+	return nil
+}
+
+func (i *Interpreter) destructure(d *ast.Destructure) (*ast.Literal, error) {
+	value, err := i.evaluate(d.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	elements, err := destructure(d.Bracket, value, len(d.Targets))
+	if err != nil {
+		return nil, err
+	}
+
+	for n, target := range d.Targets {
+		_, set, err := i.reference(target)
+		if err != nil {
+			return nil, err
+		}
+
+		err = set(&ast.Literal{Value: elements[n]})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
+
+// destructure returns the elements of "value", after checking that it's
+// a list or tuple with exactly "count" elements.
+func destructure(bracket token.Token, value *ast.Literal, count int) ([]interface{}, error) {
+	elements, ok := unpack(value.Value)
+	if !ok {
+		return nil, &Error{bracket, fmt.Sprintf("Can only destructure lists and tuples, got %s.", typeName(value.Value))}
+	}
+
+	if len(elements) != count {
+		return nil, &Error{bracket, fmt.Sprintf("Expected %d values to destructure but got %d.", count, len(elements))}
+	}
+
+	return elements, nil
+}
@@ -0,0 +1,59 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDestructuring(t *testing.T) {
+	record, recorded := recorder()
+
+	err := run(t, `
+fun pair(a, b) { return a, b; }
+var [x, y] = pair(1, 2);
+record(x);
+record(y);
+record(pair(1, 2) == pair(1, 2));
+record(pair(1, 2)[1]);
+
+[x, y] = [y, x];
+record([x, y]);
+
+class Box {}
+var box = Box();
+var list = [0, 0];
+[box.value, list[1]] = pair("a", "b");
+record([box.value, list]);
+
+fun f() {
+  const [first, second] = pair(3, 4);
+  return second, first;
+}
+record(f());
+
+try { var [a] = pair(1, 2); } catch (e) { record(e.message); }
+try { [x, y] = "xy"; } catch (e) { record(e.message); }
+try { var t = pair(1, 2); t[0] = 3; } catch (e) { record(e.message); }
+`, Natives(record))
+	if err != nil {
+		t.Fatalf("while running: %s", err)
+	}
+
+	expected := []interface{}{
+		1.0,
+		2.0,
+		true,
+		2.0,
+		&LoxList{Elements: []interface{}{2.0, 1.0}},
+		&LoxList{Elements: []interface{}{"a", &LoxList{Elements: []interface{}{0.0, "b"}}}},
+		&LoxTuple{Elements: []interface{}{4.0, 3.0}},
+		"Expected 1 values to destructure but got 2.",
+		"Can only destructure lists and tuples, got string.",
+		"Tuples can't be modified.",
+	}
+
+	if diff := cmp.Diff(expected, *recorded); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
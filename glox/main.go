@@ -3,15 +3,18 @@ package main
 import (
 	"bufio"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/ggilmore/bradfield-languages/glox/errutil"
 	"github.com/ggilmore/bradfield-languages/glox/interpreter"
 	"github.com/ggilmore/bradfield-languages/glox/parser"
 	"github.com/ggilmore/bradfield-languages/glox/scanner"
+	"github.com/ggilmore/bradfield-languages/glox/system"
 )
 
 const (
@@ -20,28 +23,40 @@ const (
 	ExRuntime = 70
 )
 
+var seed = flag.Int64("seed", 0, "seed for the random number natives (defaults to the current time)")
+
 func main() {
-	if len(os.Args) > 2 {
-		fmt.Fprintln(os.Stderr, "Usage: glox [script]")
-		os.Exit(ExUsage)
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: glox [flags] [script [arguments...]]")
+		flag.PrintDefaults()
 	}
 
-	if len(os.Args) == 2 {
-		file := os.Args[1]
-		runFile(file)
+	flag.Parse()
+
+	var options []interpreter.Option
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			options = append(options, interpreter.Seed(*seed))
+		}
+	})
+
+	if flag.NArg() > 0 {
+		// everything after the script is passed on to it
+		runFile(flag.Arg(0), flag.Args()[1:], options...)
 	} else {
-		runPrompt(os.Stdin)
+		runPrompt(os.Stdin, options...)
 	}
 }
 
-func runFile(path string) {
+func runFile(path string, args []string, options ...interpreter.Option) {
 	f, err := os.Open(path)
 	if err != nil {
 		printError(fmt.Errorf("opening %q: %w", path, err))
 		die(err)
 	}
 
-	runner := newRunner()
+	options = append(options, interpreter.ScriptPath(path), system.Option(args, os.Stdin))
+	runner := newRunner(options...)
 	err = runner.Run(f)
 	if err != nil {
 		printError(fmt.Errorf("running %q: %w", path, err))
@@ -49,8 +64,10 @@ func runFile(path string) {
 	}
 }
 
-func runPrompt(r io.Reader) {
-	runner := newRunner()
+func runPrompt(r io.Reader, options ...interpreter.Option) {
+	// the prompt reads its input from "r", so scripts can't read stdin
+	options = append(options, system.Option(nil, strings.NewReader("")))
+	runner := newRunner(options...)
 	s := bufio.NewScanner(r)
 
 	prompt := "> "
@@ -82,9 +99,14 @@ type runner struct {
 	interpreter *interpreter.Interpreter
 }
 
-func newRunner() *runner {
+func newRunner(options ...interpreter.Option) *runner {
+	// GLOX_PATH lists extra directories to look for imports in
+	if searchPath := os.Getenv("GLOX_PATH"); searchPath != "" {
+		options = append(options, interpreter.SearchPath(filepath.SplitList(searchPath)...))
+	}
+
 	return &runner{
-		interpreter: interpreter.New(),
+		interpreter: interpreter.New(options...),
 	}
 }
 
@@ -106,6 +128,11 @@ func (r *runner) Run(input io.Reader) error {
 
 	resolver := interpreter.NewResolver(r.interpreter)
 	err = resolver.Resolve(statements)
+
+	for _, w := range resolver.Warnings() {
+		fmt.Fprintln(os.Stderr, w)
+	}
+
 	if err != nil {
 		return fmt.Errorf("while resolving: %w", err)
 	}
@@ -119,6 +146,11 @@ func (r *runner) Run(input io.Reader) error {
 }
 
 func die(e error) {
+	var exit *system.ExitError
+	if errors.As(e, &exit) {
+		os.Exit(exit.Code)
+	}
+
 	var runErr interpreter.Error
 	if errors.Is(e, &runErr) {
 		os.Exit(ExRuntime)
@@ -133,6 +165,12 @@ func die(e error) {
 }
 
 func printError(err error) {
+	var exit *system.ExitError
+	if errors.As(err, &exit) {
+		// exiting isn't an error as far as the user is concerned
+		return
+	}
+
 	var e errutil.LoxLanguageError
 	if errors.As(err, &e) {
 		// only print the underlying error if it's a lox
@@ -2,6 +2,8 @@ package parser
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/ggilmore/bradfield-languages/glox/ast"
 	"github.com/ggilmore/bradfield-languages/glox/token"
@@ -13,7 +15,100 @@ type Parser struct {
 }
 
 func NewParser(tokens []token.Token) *Parser {
-	return &Parser{tokens: tokens}
+	return &Parser{tokens: dropStrayDocComments(tokens)}
+}
+
+// dropStrayDocComments removes the doc comments that can't be attached to
+// a declaration, so that the rest of the parser never has to expect them
+// in the middle of a statement or expression. A doc comment is only kept
+// if it starts a statement, outside of any parentheses or brackets, and
+// it's directly followed by "class", "fun", "var" or "const" (or by a
+// method's name, inside of a class body).
+func dropStrayDocComments(tokens []token.Token) []token.Token {
+	var out []token.Token
+
+	// enclosing holds the kind of each bracket that's open at the current
+	// token, with KindClass standing in for the brace of a class body
+	var enclosing []token.Kind
+
+	for i, t := range tokens {
+		switch t.Kind {
+		case token.KindLeftParen, token.KindLeftBracket:
+			enclosing = append(enclosing, t.Kind)
+		case token.KindLeftBrace:
+			if opensClassBody(out) {
+				enclosing = append(enclosing, token.KindClass)
+			} else {
+				enclosing = append(enclosing, t.Kind)
+			}
+		case token.KindRightParen, token.KindRightBracket, token.KindRightBrace:
+			if len(enclosing) > 0 {
+				enclosing = enclosing[:len(enclosing)-1]
+			}
+		}
+
+		if t.Kind != token.KindDocComment {
+			out = append(out, t)
+			continue
+		}
+
+		atStatementStart := true
+		for j := len(out) - 1; j >= 0; j-- {
+			if out[j].Kind == token.KindDocComment {
+				continue
+			}
+
+			switch out[j].Kind {
+			case token.KindSemicolon, token.KindLeftBrace, token.KindRightBrace:
+			default:
+				atStatementStart = false
+			}
+
+			break
+		}
+
+		next := token.KindEOF
+		for _, u := range tokens[i+1:] {
+			if u.Kind != token.KindDocComment {
+				next = u.Kind
+				break
+			}
+		}
+
+		inside := token.KindLeftBrace
+		if len(enclosing) > 0 {
+			inside = enclosing[len(enclosing)-1]
+		}
+
+		var documentsDeclaration bool
+		switch inside {
+		case token.KindLeftBrace:
+			switch next {
+			case token.KindClass, token.KindFun, token.KindVar, token.KindConst:
+				documentsDeclaration = true
+			}
+		case token.KindClass:
+			documentsDeclaration = next == token.KindIdentifier
+		}
+
+		if atStatementStart && documentsDeclaration {
+			out = append(out, t)
+		}
+	}
+
+	return out
+}
+
+// opensClassBody reports whether a "{" that follows "previous" starts the
+// body of a class, i.e. "previous" ends with "class Name" or
+// "class Name < Superclass".
+func opensClassBody(previous []token.Token) bool {
+	n := len(previous)
+	if n >= 2 && previous[n-2].Kind == token.KindClass {
+		return true
+	}
+
+	return n >= 4 && previous[n-4].Kind == token.KindClass && previous[n-2].Kind == token.KindLess
 }
 
 func (p *Parser) Parse() ([]ast.Statement, error) {
@@ -36,18 +131,127 @@ func (p *Parser) Parse() ([]ast.Statement, error) {
 }
 
 func (p *Parser) declaration() (ast.Statement, error) {
-	if p.match(token.KindFun) {
-		return p.function("function")
+	doc := p.docComment()
+
+	if p.match(token.KindClass) {
+		class, err := p.classDeclaration()
+		if err != nil {
+			return nil, err
+		}
+
+		class.Doc = doc
+		return class, nil
+	}
+
+	// "fun" followed by a name is a declaration, otherwise it's the
+	// start of an anonymous function expression
+	if p.check(token.KindFun) && p.checkNext(token.KindIdentifier) {
+		p.advance()
+		function, err := p.function("function")
+		if err != nil {
+			return nil, err
+		}
+
+		function.Doc = doc
+		return function, nil
+	}
+
+	if (p.check(token.KindVar) || p.check(token.KindConst)) && p.checkNext(token.KindLeftBracket) {
+		constant := p.advance().Kind == token.KindConst
+		destructure, err := p.destructuringDeclaration(constant)
+		if err != nil {
+			return nil, err
+		}
+
+		destructure.Doc = doc
+		return destructure, nil
 	}
 
 	if p.match(token.KindVar) {
-		return p.varDeclaration()
+		variable, err := p.varDeclaration()
+		if err != nil {
+			return nil, err
+		}
+
+		variable.Doc = doc
+		return variable, nil
+	}
+
+	if p.match(token.KindConst) {
+		constant, err := p.constDeclaration()
+		if err != nil {
+			return nil, err
+		}
+
+		constant.Doc = doc
+		return constant, nil
+	}
+
+	if p.match(token.KindImport) {
+		return p.importDeclaration()
 	}
 
 	return p.statement()
 }
 
-func (p *Parser) function(kind string) (ast.Statement, error) {
+// docComment consumes any doc comments before a declaration, and returns
+// their text joined into a single string.
+func (p *Parser) docComment() string {
+	var lines []string
+	for p.match(token.KindDocComment) {
+		lines = append(lines, p.previous().Literal.(string))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (p *Parser) classDeclaration() (*ast.ClassStatement, error) {
+	name, err := p.consume(token.KindIdentifier, "Expect class name.")
+	if err != nil {
+		return nil, err
+	}
+
+	var superclass *ast.Variable
+	if p.match(token.KindLess) {
+		superName, err := p.consume(token.KindIdentifier, "Expect superclass name.")
+		if err != nil {
+			return nil, err
+		}
+
+		superclass = &ast.Variable{Identifier: superName}
+	}
+
+	_, err = p.consume(token.KindLeftBrace, "Expect '{' before class body.")
+	if err != nil {
+		return nil, err
+	}
+
+	var methods []*ast.FunctionStatement
+	for !p.check(token.KindRightBrace) && !p.isAtEnd() {
+		doc := p.docComment()
+
+		method, err := p.function("method")
+		if err != nil {
+			return nil, err
+		}
+
+		method.Doc = doc
+		methods = append(methods, method)
+	}
+
+	_, err = p.consume(token.KindRightBrace, "Expect '}' after class body.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.ClassStatement{
+		Name:       name,
+		Superclass: superclass,
+		Methods:    methods,
+	}, nil
+}
+
+func (p *Parser) function(kind string) (*ast.FunctionStatement, error) {
 	name, err := p.consume(token.KindIdentifier, fmt.Sprintf("Expect %s name.", kind))
 	if err != nil {
 		return nil, err
@@ -58,19 +262,68 @@ func (p *Parser) function(kind string) (ast.Statement, error) {
 		return nil, err
 	}
 
-	var parameters []token.Token
+	return p.functionBody(name, kind)
+}
+
+func (p *Parser) lambda() (ast.Expression, error) {
+	keyword := p.previous()
+
+	_, err := p.consume(token.KindLeftParen, "Expect '(' after 'fun'.")
+	if err != nil {
+		return nil, err
+	}
+
+	declaration, err := p.functionBody(keyword, "function")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.Lambda{Declaration: declaration}, nil
+}
+
+// functionBody parses a function's parameter list and body, starting
+// just after the opening '(' of the parameter list.
+func (p *Parser) functionBody(name token.Token, kind string) (*ast.FunctionStatement, error) {
+	function := &ast.FunctionStatement{Name: name}
+
 	if !p.check(token.KindRightParen) {
 		for {
-			if len(parameters) >= 255 {
+			if len(function.Params) >= 255 {
 				return nil, p.error(p.peek(), "Can't have more than 255 parameters.")
 			}
 
+			if p.match(token.KindDotDotDot) {
+				rest, err := p.consume(token.KindIdentifier, "Expect parameter name after '...'.")
+				if err != nil {
+					return nil, err
+				}
+
+				function.Rest = &rest
+
+				if p.check(token.KindComma) {
+					return nil, p.error(p.peek(), "Rest parameter must be the last parameter.")
+				}
+
+				break
+			}
+
 			param, err := p.consume(token.KindIdentifier, "Expect parameter name.")
 			if err != nil {
 				return nil, err
 			}
 
-			parameters = append(parameters, param)
+			var defaultValue ast.Expression
+			if p.match(token.KindEqual) {
+				defaultValue, err = p.expression()
+				if err != nil {
+					return nil, err
+				}
+			} else if len(function.Defaults) > 0 && function.Defaults[len(function.Defaults)-1] != nil {
+				return nil, p.error(param, "Parameters without default values can't follow ones with them.")
+			}
+
+			function.Params = append(function.Params, param)
+			function.Defaults = append(function.Defaults, defaultValue)
 
 			if !p.match(token.KindComma) {
 				break
@@ -78,7 +331,7 @@ func (p *Parser) function(kind string) (ast.Statement, error) {
 		}
 	}
 
-	_, err = p.consume(token.KindRightParen, "Expect ')' after parameters.")
+	_, err := p.consume(token.KindRightParen, "Expect ')' after parameters.")
 	if err != nil {
 		return nil, err
 	}
@@ -93,14 +346,85 @@ func (p *Parser) function(kind string) (ast.Statement, error) {
 		return nil, err
 	}
 
-	return &ast.FunctionStatement{
-		Name:   name,
-		Params: parameters,
-		Body:   body,
-	}, nil
+	function.Body = body
+	return function, nil
+}
+
+func (p *Parser) importDeclaration() (ast.Statement, error) {
+	keyword := p.previous()
+
+	path, err := p.consume(token.KindString, "Expect module path after 'import'.")
+	if err != nil {
+		return nil, err
+	}
+
+	var name token.Token
+	if p.match(token.KindAs) {
+		name, err = p.consume(token.KindIdentifier, "Expect module name after 'as'.")
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// default to the file name without its extension, which
+		// has to be usable as a variable name
+		base := filepath.Base(path.Literal.(string))
+		lexeme := strings.TrimSuffix(base, filepath.Ext(base))
+		if !isIdentifier(lexeme) {
+			return nil, p.error(path, fmt.Sprintf("Expect 'as' and a name for module %q.", base))
+		}
+
+		name = token.Token{Kind: token.KindIdentifier, Lexeme: lexeme, Line: path.Line}
+	}
+
+	_, err = p.consume(token.KindSemicolon, "Expect ';' after import.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.ImportStatement{Keyword: keyword, Path: path, Name: name}, nil
+}
+
+// isIdentifier reports whether "s" would be scanned as an identifier.
+func isIdentifier(s string) bool {
+	if _, isKeyword := token.Keywords[s]; isKeyword || s == "" {
+		return false
+	}
+
+	for i, c := range s {
+		isAlpha := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+		if !isAlpha && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p *Parser) constDeclaration() (*ast.VarStatement, error) {
+	name, err := p.consume(token.KindIdentifier, "Expect constant name.")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.KindEqual, "Expect '=' after constant name.")
+	if err != nil {
+		return nil, err
+	}
+
+	initializer, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.KindSemicolon, "Expect ';' after constant declaration.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.VarStatement{Name: name, Initializer: initializer, Const: true}, nil
 }
 
-func (p *Parser) varDeclaration() (ast.Statement, error) {
+func (p *Parser) varDeclaration() (*ast.VarStatement, error) {
 	name, err := p.consume(token.KindIdentifier, "Expect variable name.")
 	if err != nil {
 		return nil, err
@@ -124,7 +448,63 @@ func (p *Parser) varDeclaration() (ast.Statement, error) {
 	return &ast.VarStatement{Name: name, Initializer: initializer}, nil
 }
 
-func (p *Parser) while() (ast.Statement, error) {
+// destructuringDeclaration parses the rest of a "var [x, y] = value;" or
+// "const [x, y] = value;" declaration, after the keyword.
+func (p *Parser) destructuringDeclaration(constant bool) (*ast.DestructureStatement, error) {
+	bracket, err := p.consume(token.KindLeftBracket, "Expect '[' before names.")
+	if err != nil {
+		return nil, err
+	}
+
+	var names []token.Token
+	seen := make(map[string]bool)
+	for {
+		name, err := p.consume(token.KindIdentifier, "Expect variable name.")
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[name.Lexeme] {
+			return nil, p.error(name, fmt.Sprintf("Duplicate name %q in destructuring declaration.", name.Lexeme))
+		}
+
+		seen[name.Lexeme] = true
+		names = append(names, name)
+
+		if !p.match(token.KindComma) {
+			break
+		}
+	}
+
+	_, err = p.consume(token.KindRightBracket, "Expect ']' after names.")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.KindEqual, "Expect '=' after destructuring pattern.")
+	if err != nil {
+		return nil, err
+	}
+
+	initializer, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.KindSemicolon, "Expect ';' after variable declaration.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.DestructureStatement{
+		Bracket:     bracket,
+		Names:       names,
+		Initializer: initializer,
+		Const:       constant,
+	}, nil
+}
+
+func (p *Parser) while(label *token.Token) (ast.Statement, error) {
 	_, err := p.consume(token.KindLeftParen, "Expect '(' after while.")
 	if err != nil {
 		return nil, err
@@ -146,6 +526,7 @@ func (p *Parser) while() (ast.Statement, error) {
 	}
 
 	return &ast.WhileStatement{
+		Label:     label,
 		Condition: cond,
 		Body:      body,
 	}, nil
@@ -156,7 +537,9 @@ func (p *Parser) statement() (ast.Statement, error) {
 		return p.printStatement()
 	}
 
-	if p.match(token.KindLeftBrace) {
+	// a '{' at the start of a statement is a block, unless it's
+	// clearly the start of a map literal
+	if !p.startsMapLiteral() && p.match(token.KindLeftBrace) {
 		statements, err := p.block()
 		if err != nil {
 			return nil, err
@@ -165,12 +548,28 @@ func (p *Parser) statement() (ast.Statement, error) {
 		return &ast.BlockStatement{Statements: statements}, nil
 	}
 
+	if p.check(token.KindIdentifier) && p.checkNext(token.KindColon) {
+		return p.labeledStatement()
+	}
+
 	if p.match(token.KindFor) {
-		return p.forStatement()
+		return p.forStatement(nil)
 	}
 
 	if p.match(token.KindWhile) {
-		return p.while()
+		return p.while(nil)
+	}
+
+	if p.match(token.KindMatch) {
+		return p.matchStatement()
+	}
+
+	if p.match(token.KindBreak) {
+		return p.breakStatement()
+	}
+
+	if p.match(token.KindContinue) {
+		return p.continueStatement()
 	}
 
 	if p.match(token.KindIf) {
@@ -181,9 +580,215 @@ func (p *Parser) statement() (ast.Statement, error) {
 		return p.returnStatement()
 	}
 
+	if p.match(token.KindThrow) {
+		return p.throwStatement()
+	}
+
+	if p.match(token.KindTry) {
+		return p.tryStatement()
+	}
+
 	return p.expressionStatement()
 }
 
+func (p *Parser) labeledStatement() (ast.Statement, error) {
+	label := p.advance()
+
+	// consume the ':'
+	p.advance()
+
+	if p.match(token.KindFor) {
+		return p.forStatement(&label)
+	}
+
+	if p.match(token.KindWhile) {
+		return p.while(&label)
+	}
+
+	return nil, p.error(p.peek(), "Expect loop after label.")
+}
+
+func (p *Parser) matchStatement() (ast.Statement, error) {
+	keyword := p.previous()
+
+	_, err := p.consume(token.KindLeftParen, "Expect '(' after 'match'.")
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.KindRightParen, "Expect ')' after match subject.")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.KindLeftBrace, "Expect '{' before match arms.")
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &ast.MatchStatement{Keyword: keyword, Subject: subject}
+	for p.match(token.KindCase) {
+		arm, err := p.matchArm()
+		if err != nil {
+			return nil, err
+		}
+
+		statement.Arms = append(statement.Arms, arm)
+	}
+
+	if p.match(token.KindElse) {
+		_, err := p.consume(token.KindFatArrow, "Expect '=>' after 'else'.")
+		if err != nil {
+			return nil, err
+		}
+
+		elseBranch, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+
+		statement.Else = elseBranch
+	}
+
+	_, err = p.consume(token.KindRightBrace, "Expect '}' after match arms.")
+	if err != nil {
+		return nil, err
+	}
+
+	return statement, nil
+}
+
+func (p *Parser) matchArm() (*ast.MatchArm, error) {
+	arm := &ast.MatchArm{Keyword: p.previous()}
+
+	for {
+		pattern, err := p.matchPattern()
+		if err != nil {
+			return nil, err
+		}
+
+		arm.Patterns = append(arm.Patterns, pattern)
+
+		if !p.match(token.KindComma) {
+			break
+		}
+	}
+
+	if p.match(token.KindIf) {
+		guard, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		arm.Guard = guard
+	}
+
+	_, err := p.consume(token.KindFatArrow, "Expect '=>' after case patterns.")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+
+	arm.Body = body
+	return arm, nil
+}
+
+func (p *Parser) matchPattern() (*ast.MatchPattern, error) {
+	first := p.peek()
+
+	value, err := p.patternLiteral()
+	if err != nil {
+		return nil, err
+	}
+
+	pattern := &ast.MatchPattern{Token: first, Value: value}
+	if p.match(token.KindDotDot, token.KindDotDotEqual) {
+		operator := p.previous()
+		high, err := p.patternLiteral()
+		if err != nil {
+			return nil, err
+		}
+
+		_, lowIsNumber := value.Value.(float64)
+		_, highIsNumber := high.Value.(float64)
+		if !lowIsNumber || !highIsNumber {
+			return nil, p.error(operator, "Range patterns must be between two numbers.")
+		}
+
+		pattern.High = high
+		pattern.Inclusive = operator.Kind == token.KindDotDotEqual
+	}
+
+	return pattern, nil
+}
+
+// patternLiteral parses a literal inside of a match pattern: a string,
+// boolean, nil, or a (possibly negated) number.
+func (p *Parser) patternLiteral() (*ast.Literal, error) {
+	switch {
+	case p.match(token.KindTrue):
+		return &ast.Literal{Value: true}, nil
+	case p.match(token.KindFalse):
+		return &ast.Literal{Value: false}, nil
+	case p.match(token.KindNil):
+		return &ast.Literal{Value: nil}, nil
+	case p.match(token.KindNumber, token.KindString):
+		return &ast.Literal{Value: p.previous().Literal}, nil
+	case p.match(token.KindMinus):
+		n, err := p.consume(token.KindNumber, "Expect number after '-' in pattern.")
+		if err != nil {
+			return nil, err
+		}
+
+		return &ast.Literal{Value: -n.Literal.(float64)}, nil
+	}
+
+	return nil, p.error(p.peek(), "Expect literal pattern.")
+}
+
+func (p *Parser) breakStatement() (ast.Statement, error) {
+	keyword := p.previous()
+
+	var label *token.Token
+	if p.match(token.KindIdentifier) {
+		name := p.previous()
+		label = &name
+	}
+
+	_, err := p.consume(token.KindSemicolon, "Expect ';' after 'break'.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.BreakStatement{Keyword: keyword, Label: label}, nil
+}
+
+func (p *Parser) continueStatement() (ast.Statement, error) {
+	keyword := p.previous()
+
+	var label *token.Token
+	if p.match(token.KindIdentifier) {
+		name := p.previous()
+		label = &name
+	}
+
+	_, err := p.consume(token.KindSemicolon, "Expect ';' after 'continue'.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.ContinueStatement{Keyword: keyword, Label: label}, nil
+}
+
 func (p *Parser) printStatement() (ast.Statement, error) {
 	value, err := p.expression()
 	if err != nil {
@@ -208,6 +813,21 @@ func (p *Parser) returnStatement() (ast.Statement, error) {
 		}
 
 		value = expr
+
+		// "return a, b;" returns a tuple of the values
+		if p.check(token.KindComma) {
+			tuple := &ast.Tuple{Elements: []ast.Expression{expr}}
+			for p.match(token.KindComma) {
+				element, err := p.expression()
+				if err != nil {
+					return nil, err
+				}
+
+				tuple.Elements = append(tuple.Elements, element)
+			}
+
+			value = tuple
+		}
 	}
 
 	_, err := p.consume(token.KindSemicolon, "Expect ';' after return value.")
@@ -218,12 +838,100 @@ func (p *Parser) returnStatement() (ast.Statement, error) {
 	return &ast.ReturnStatement{Keyword: keyword, Value: value}, nil
 }
 
-func (p *Parser) forStatement() (ast.Statement, error) {
+func (p *Parser) throwStatement() (ast.Statement, error) {
+	keyword := p.previous()
+
+	value, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.KindSemicolon, "Expect ';' after thrown value.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.ThrowStatement{Keyword: keyword, Value: value}, nil
+}
+
+func (p *Parser) tryStatement() (ast.Statement, error) {
+	statement := &ast.TryStatement{Keyword: p.previous()}
+
+	body, err := p.blockStatement("Expect '{' after 'try'.")
+	if err != nil {
+		return nil, err
+	}
+
+	statement.Body = body
+
+	if p.match(token.KindCatch) {
+		_, err := p.consume(token.KindLeftParen, "Expect '(' after 'catch'.")
+		if err != nil {
+			return nil, err
+		}
+
+		name, err := p.consume(token.KindIdentifier, "Expect exception name.")
+		if err != nil {
+			return nil, err
+		}
+
+		statement.CatchName = &name
+
+		_, err = p.consume(token.KindRightParen, "Expect ')' after exception name.")
+		if err != nil {
+			return nil, err
+		}
+
+		catch, err := p.blockStatement("Expect '{' after catch clause.")
+		if err != nil {
+			return nil, err
+		}
+
+		statement.Catch = catch
+	}
+
+	if p.match(token.KindFinally) {
+		finally, err := p.blockStatement("Expect '{' after 'finally'.")
+		if err != nil {
+			return nil, err
+		}
+
+		statement.Finally = finally
+	}
+
+	if statement.Catch == nil && statement.Finally == nil {
+		return nil, p.error(p.peek(), "Expect 'catch' or 'finally' after try block.")
+	}
+
+	return statement, nil
+}
+
+// blockStatement consumes a '{' (reporting "message" if it's missing)
+// followed by the rest of a block.
+func (p *Parser) blockStatement(message string) (*ast.BlockStatement, error) {
+	_, err := p.consume(token.KindLeftBrace, message)
+	if err != nil {
+		return nil, err
+	}
+
+	statements, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.BlockStatement{Statements: statements}, nil
+}
+
+func (p *Parser) forStatement(label *token.Token) (ast.Statement, error) {
 	_, err := p.consume(token.KindLeftParen, "Expect '(' after 'for'.")
 	if err != nil {
 		return nil, err
 	}
 
+	if p.check(token.KindIdentifier) && p.checkNext(token.KindIn) {
+		return p.forInStatement(label)
+	}
+
 	var initializer ast.Statement
 	if p.match(token.KindSemicolon) {
 		initializer = nil
@@ -276,23 +984,16 @@ func (p *Parser) forStatement() (ast.Statement, error) {
 		return nil, err
 	}
 
-	if increment != nil {
-		body = &ast.BlockStatement{
-			Statements: []ast.Statement{
-				body,
-				&ast.ExpressionStatement{Expression: increment},
-			},
-		}
-	}
-
 	if condition == nil {
 		var trueExpr ast.Expression = &ast.Literal{Value: true}
 		condition = trueExpr
 	}
 
 	body = &ast.WhileStatement{
+		Label:     label,
 		Condition: condition,
 		Body:      body,
+		Increment: increment,
 	}
 
 	if initializer != nil {
@@ -304,7 +1005,37 @@ func (p *Parser) forStatement() (ast.Statement, error) {
 		}
 	}
 
-	return body, nil
+	return body, nil
+}
+
+// forInStatement parses the rest of a "for (x in iterable)" loop, after
+// the opening parenthesis.
+func (p *Parser) forInStatement(label *token.Token) (ast.Statement, error) {
+	name := p.advance()
+	in := p.advance()
+
+	iterable, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.KindRightParen, "Expect ')' after for clauses.")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.ForInStatement{
+		Label:    label,
+		Name:     name,
+		In:       in,
+		Iterable: iterable,
+		Body:     body,
+	}, nil
 }
 
 func (p *Parser) ifStatement() (ast.Statement, error) {
@@ -460,7 +1191,7 @@ func (p *Parser) assignment() (ast.Expression, error) {
 		return nil, p.error(p.peek(), "expected identifier")
 	}
 
-	expr, err := p.or()
+	expr, err := p.conditional()
 	if err != nil {
 		return nil, err
 	}
@@ -472,13 +1203,121 @@ func (p *Parser) assignment() (ast.Expression, error) {
 			return nil, err
 		}
 
-		variableExpr, ok := expr.(*ast.Variable)
-		if !ok {
-			return nil, p.error(equals, "Invalid assignment target.")
+		switch target := expr.(type) {
+		case *ast.Variable:
+			return &ast.Assignment{Name: target.Identifier, Value: value}, nil
+		case *ast.Get:
+			return &ast.Set{Object: target.Object, Name: target.Name, Value: value}, nil
+		case *ast.Index:
+			return &ast.IndexSet{Object: target.Object, Bracket: target.Bracket, Index: target.Index, Value: value}, nil
+		case *ast.List:
+			if isDestructurable(target) {
+				return &ast.Destructure{Bracket: target.Bracket, Targets: target.Elements, Value: value}, nil
+			}
+		}
+
+		return nil, p.error(equals, "Invalid assignment target.")
+	}
+
+	if p.match(token.KindPlusEqual, token.KindMinusEqual, token.KindStarEqual, token.KindSlashEqual) {
+		operator := p.previous()
+		value, err := p.assignment()
+		if err != nil {
+			return nil, err
+		}
+
+		if !isAssignable(expr) {
+			return nil, p.error(operator, "Invalid assignment target.")
+		}
+
+		return &ast.CompoundAssignment{Target: expr, Operator: operator, Value: value}, nil
+	}
+
+	return expr, nil
+}
+
+// isAssignable reports whether "expr" can be the target of a compound
+// assignment, or an increment or decrement.
+func isAssignable(expr ast.Expression) bool {
+	switch expr.(type) {
+	case *ast.Variable, *ast.Get, *ast.Index:
+		return true
+	}
+
+	return false
+}
+
+// isDestructurable reports whether a list literal can be the target of a
+// destructuring assignment, which means that it's non-empty and that each
+// of its elements can be assigned to.
+func isDestructurable(l *ast.List) bool {
+	if len(l.Elements) == 0 {
+		return false
+	}
+
+	for _, e := range l.Elements {
+		if !isAssignable(e) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// conditional parses the ternary operator, which is right-associative:
+// "a ? b : c ? d : e" is "a ? b : (c ? d : e)".
+func (p *Parser) conditional() (ast.Expression, error) {
+	expr, err := p.coalesce()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(token.KindQuestion) {
+		question := p.previous()
+		thenBranch, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.consume(token.KindColon, "Expect ':' after then branch of conditional expression.")
+		if err != nil {
+			return nil, err
+		}
+
+		elseBranch, err := p.conditional()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.Conditional{
+			Condition: expr,
+			Question:  question,
+			Then:      thenBranch,
+			Else:      elseBranch,
+		}
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) coalesce() (ast.Expression, error) {
+	expr, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.KindQuestionQuestion) {
+		operator := p.previous()
+		right, err := p.or()
+		if err != nil {
+			return nil, err
 		}
 
-		name := variableExpr.Identifier
-		return &ast.Assignment{Name: name, Value: value}, nil
+		expr = &ast.Logical{
+			Left:     expr,
+			Operator: operator,
+			Right:    right,
+		}
 	}
 
 	return expr, nil
@@ -508,14 +1347,14 @@ func (p *Parser) equality() (ast.Expression, error) {
 }
 
 func (p *Parser) comparsion() (ast.Expression, error) {
-	expr, err := p.term()
+	expr, err := p.rangeExpression()
 	if err != nil {
 		return nil, err
 	}
 
 	for p.match(token.KindGreater, token.KindGreaterEqual, token.KindLess, token.KindLessEqual) {
 		operator := p.previous()
-		right, err := p.term()
+		right, err := p.rangeExpression()
 		if err != nil {
 			return nil, err
 		}
@@ -529,6 +1368,27 @@ func (p *Parser) comparsion() (ast.Expression, error) {
 	return expr, nil
 }
 
+// rangeExpression parses "start..end" and "start..=end". Ranges don't
+// chain, so "a..b..c" is a syntax error.
+func (p *Parser) rangeExpression() (ast.Expression, error) {
+	expr, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(token.KindDotDot, token.KindDotDotEqual) {
+		operator := p.previous()
+		end, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.Range{Start: expr, Operator: operator, End: end}
+	}
+
+	return expr, nil
+}
+
 func (p *Parser) term() (ast.Expression, error) {
 	expr, err := p.factor()
 	if err != nil {
@@ -558,7 +1418,7 @@ func (p *Parser) factor() (ast.Expression, error) {
 		return nil, err
 	}
 
-	for p.match(token.KindSlash, token.KindStar) {
+	for p.match(token.KindSlash, token.KindStar, token.KindPercent, token.KindTildeSlash) {
 		operator := p.previous()
 		right, err := p.unary()
 		if err != nil {
@@ -589,7 +1449,71 @@ func (p *Parser) unary() (ast.Expression, error) {
 		}, nil
 	}
 
-	return p.call()
+	return p.exponent()
+}
+
+// exponent parses "**", which is right-associative and binds tighter than
+// the unary operators on its left, so that -2 ** 2 is -(2 ** 2). Its right
+// operand can still be negated, as in 2 ** -1.
+func (p *Parser) exponent() (ast.Expression, error) {
+	expr, err := p.prefix()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(token.KindStarStar) {
+		operator := p.previous()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.Binary{
+			Left:     expr,
+			Operator: operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+// prefix parses "++x" and "--x". Its operand is parsed below the exponent
+// level, so "++x ** 2" squares the incremented value.
+func (p *Parser) prefix() (ast.Expression, error) {
+	if p.match(token.KindPlusPlus, token.KindMinusMinus) {
+		operator := p.previous()
+		target, err := p.postfix()
+		if err != nil {
+			return nil, err
+		}
+
+		if !isAssignable(target) {
+			return nil, p.error(operator, fmt.Sprintf("Invalid operand for '%s'.", operator.Lexeme))
+		}
+
+		return &ast.Update{Target: target, Operator: operator, Prefix: true}, nil
+	}
+
+	return p.postfix()
+}
+
+func (p *Parser) postfix() (ast.Expression, error) {
+	expr, err := p.call()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(token.KindPlusPlus, token.KindMinusMinus) {
+		operator := p.previous()
+		if !isAssignable(expr) {
+			return nil, p.error(operator, fmt.Sprintf("Invalid operand for '%s'.", operator.Lexeme))
+		}
+
+		return &ast.Update{Target: expr, Operator: operator, Prefix: false}, nil
+	}
+
+	return expr, nil
 }
 
 func (p *Parser) call() (ast.Expression, error) {
@@ -604,6 +1528,25 @@ func (p *Parser) call() (ast.Expression, error) {
 			if err != nil {
 				return nil, err
 			}
+		} else if p.match(token.KindDot) {
+			name, err := p.consume(token.KindIdentifier, "Expect property name after '.'.")
+			if err != nil {
+				return nil, err
+			}
+
+			expr = &ast.Get{Object: expr, Name: name}
+		} else if p.match(token.KindLeftBracket) {
+			index, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+
+			bracket, err := p.consume(token.KindRightBracket, "Expect ']' after index.")
+			if err != nil {
+				return nil, err
+			}
+
+			expr = &ast.Index{Object: expr, Bracket: bracket, Index: index}
 		} else {
 			break
 		}
@@ -620,11 +1563,21 @@ func (p *Parser) finishCall(callee ast.Expression) (ast.Expression, error) {
 				return nil, p.error(p.peek(), "Can't have more than 255 arguments.")
 			}
 
+			var ellipsis *token.Token
+			if p.match(token.KindDotDotDot) {
+				t := p.previous()
+				ellipsis = &t
+			}
+
 			expr, err := p.expression()
 			if err != nil {
 				return nil, err
 			}
 
+			if ellipsis != nil {
+				expr = &ast.Spread{Ellipsis: *ellipsis, Value: expr}
+			}
+
 			arguments = append(arguments, expr)
 
 			if !p.match(token.KindComma) {
@@ -660,14 +1613,49 @@ func (p *Parser) primary() (ast.Expression, error) {
 		return &ast.Literal{Value: p.previous().Literal}, nil
 	}
 
+	if p.match(token.KindInterpolation) {
+		return p.interpolation()
+	}
+
 	if p.match(token.KindDebug) {
 		return &ast.Debug{}, nil
 	}
 
+	if p.match(token.KindSuper) {
+		keyword := p.previous()
+		_, err := p.consume(token.KindDot, "Expect '.' after 'super'.")
+		if err != nil {
+			return nil, err
+		}
+
+		method, err := p.consume(token.KindIdentifier, "Expect superclass method name.")
+		if err != nil {
+			return nil, err
+		}
+
+		return &ast.Super{Keyword: keyword, Method: method}, nil
+	}
+
+	if p.match(token.KindThis) {
+		return &ast.This{Keyword: p.previous()}, nil
+	}
+
 	if p.match(token.KindIdentifier) {
 		return &ast.Variable{Identifier: p.previous()}, nil
 	}
 
+	if p.match(token.KindFun) {
+		return p.lambda()
+	}
+
+	if p.match(token.KindLeftBracket) {
+		return p.list()
+	}
+
+	if p.match(token.KindLeftBrace) {
+		return p.mapLiteral()
+	}
+
 	if p.match(token.KindLeftParen) {
 		expr, err := p.expression()
 		if err != nil {
@@ -687,6 +1675,132 @@ func (p *Parser) primary() (ast.Expression, error) {
 	return nil, p.error(p.peek(), "expected expression.")
 }
 
+// interpolation parses an interpolated string into the concatenation
+// of its literal parts and the stringified values of its expressions.
+// The scanner emits `"a ${b} c ${d} e"` as:
+//
+//	Interpolation("a ") b Interpolation(" c ") d String(" e")
+func (p *Parser) interpolation() (ast.Expression, error) {
+	var expr ast.Expression
+	concat := func(part ast.Expression) {
+		if expr == nil {
+			expr = part
+			return
+		}
+
+		plus := token.Token{Kind: token.KindPlus, Lexeme: "+", Line: p.previous().Line}
+		expr = &ast.Binary{Left: expr, Operator: plus, Right: part}
+	}
+
+	fragment := p.previous()
+	for {
+		if fragment.Literal != "" {
+			concat(&ast.Literal{Value: fragment.Literal})
+		}
+
+		value, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		concat(&ast.Stringify{Expression: value})
+
+		if p.match(token.KindInterpolation) {
+			fragment = p.previous()
+			continue
+		}
+
+		end, err := p.consume(token.KindString, "Expect '}' after interpolated expression.")
+		if err != nil {
+			return nil, err
+		}
+
+		if end.Literal != "" {
+			concat(&ast.Literal{Value: end.Literal})
+		}
+
+		return expr, nil
+	}
+}
+
+func (p *Parser) list() (ast.Expression, error) {
+	bracket := p.previous()
+
+	var elements []ast.Expression
+	for !p.check(token.KindRightBracket) && !p.isAtEnd() {
+		element, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		elements = append(elements, element)
+
+		if !p.match(token.KindComma) {
+			break
+		}
+	}
+
+	_, err := p.consume(token.KindRightBracket, "Expect ']' after list elements.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.List{Bracket: bracket, Elements: elements}, nil
+}
+
+func (p *Parser) mapLiteral() (ast.Expression, error) {
+	brace := p.previous()
+
+	var keys, values []ast.Expression
+	for !p.check(token.KindRightBrace) && !p.isAtEnd() {
+		key, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.consume(token.KindColon, "Expect ':' after map key.")
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+		values = append(values, value)
+
+		if !p.match(token.KindComma) {
+			break
+		}
+	}
+
+	_, err := p.consume(token.KindRightBrace, "Expect '}' after map entries.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.Map{Brace: brace, Keys: keys, Values: values}, nil
+}
+
+// startsMapLiteral reports whether the upcoming tokens are the start of a
+// map literal with a literal key, like `{"key": ...`. Blocks can never
+// start this way, so these are the only statements starting with '{'
+// that are treated as expressions rather than blocks.
+func (p *Parser) startsMapLiteral() bool {
+	if !p.check(token.KindLeftBrace) || p.current+2 >= len(p.tokens) {
+		return false
+	}
+
+	switch p.tokens[p.current+1].Kind {
+	case token.KindString, token.KindNumber, token.KindTrue, token.KindFalse:
+		return p.tokens[p.current+2].Kind == token.KindColon
+	}
+
+	return false
+}
+
 func (p *Parser) match(types ...token.Kind) bool {
 	for _, t := range types {
 		if p.check(t) {
@@ -706,6 +1820,14 @@ func (p *Parser) check(t token.Kind) bool {
 	return p.peek().Kind == t
 }
 
+func (p *Parser) checkNext(t token.Kind) bool {
+	if p.isAtEnd() || p.current+1 >= len(p.tokens) {
+		return false
+	}
+
+	return p.tokens[p.current+1].Kind == t
+}
+
 func (p *Parser) advance() token.Token {
 	if !p.isAtEnd() {
 		p.current++
@@ -741,16 +1863,17 @@ func (p *Parser) synchronize() {
 		if p.previous().Kind == token.KindSemicolon {
 			return
 		}
-	}
 
-	switch p.peek().Kind {
-	case
-		token.KindClass, token.KindFun, token.KindVar, token.KindFor,
-		token.KindIf, token.KindWhile, token.KindPrint, token.KindReturn:
-		return
-	}
+		switch p.peek().Kind {
+		case
+			token.KindClass, token.KindFun, token.KindVar, token.KindConst, token.KindFor,
+			token.KindIf, token.KindWhile, token.KindPrint, token.KindReturn,
+			token.KindMatch, token.KindThrow, token.KindTry, token.KindImport:
+			return
+		}
 
-	p.advance()
+		p.advance()
+	}
 }
 
 func (p *Parser) error(t token.Token, message string) error {
@@ -1 +1,159 @@
 package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+	"github.com/ggilmore/bradfield-languages/glox/scanner"
+)
+
+func TestDocComments(t *testing.T) {
+	input := `
+/// A point in space.
+class Point {
+  /// Sum of the coordinates.
+  /// Ignores z.
+  sum() {
+    /// Stray, and dropped.
+    return 1 + /// dropped too
+      2;
+    /// Trailing, and dropped.
+  }
+}
+
+/// The origin.
+var origin = Point();
+
+/// Not attached to anything.
+print origin;
+`
+
+	s, err := scanner.New(strings.NewReader(input), scanner.KeepDocComments())
+	if err != nil {
+		t.Fatalf("failed to initialize scanner: %s", err)
+	}
+
+	tokens, err := s.Scan()
+	if err != nil {
+		t.Fatalf("while scanning input: %s", err)
+	}
+
+	statements, err := NewParser(tokens).Parse()
+	if err != nil {
+		t.Fatalf("while parsing: %s", err)
+	}
+
+	if len(statements) != 3 {
+		t.Fatalf("expected 3 statements, got %d", len(statements))
+	}
+
+	class := statements[0].(*ast.ClassStatement)
+	if class.Doc != "A point in space." {
+		t.Errorf("unexpected class doc: %q", class.Doc)
+	}
+
+	if doc := class.Methods[0].Doc; doc != "Sum of the coordinates.\nIgnores z." {
+		t.Errorf("unexpected method doc: %q", doc)
+	}
+
+	if doc := statements[1].(*ast.VarStatement).Doc; doc != "The origin." {
+		t.Errorf("unexpected var doc: %q", doc)
+	}
+}
+
+func TestImportNames(t *testing.T) {
+	input := `
+import "lib/strings.lox";
+import "../my-helpers.lox" as helpers;
+import "util";
+`
+
+	s, err := scanner.New(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to initialize scanner: %s", err)
+	}
+
+	tokens, err := s.Scan()
+	if err != nil {
+		t.Fatalf("while scanning input: %s", err)
+	}
+
+	statements, err := NewParser(tokens).Parse()
+	if err != nil {
+		t.Fatalf("while parsing: %s", err)
+	}
+
+	for n, expected := range []string{"strings", "helpers", "util"} {
+		if name := statements[n].(*ast.ImportStatement).Name.Lexeme; name != expected {
+			t.Errorf("statement %d: expected module name %q, got %q", n, expected, name)
+		}
+	}
+}
+
+func TestStrayDocComments(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "inside of a map literal",
+			input: "var m = { /// doc\n \"a\": 1 };",
+		},
+		{
+			name:  "between for clauses",
+			input: "for (var i = 0; /// doc\n i < 1; i = i + 1) {}",
+		},
+		{
+			name:  "before else",
+			input: "if (true) {} /// doc\n else {}",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := scanner.New(strings.NewReader(tt.input), scanner.KeepDocComments())
+			if err != nil {
+				t.Fatalf("failed to initialize scanner: %s", err)
+			}
+
+			tokens, err := s.Scan()
+			if err != nil {
+				t.Fatalf("while scanning input: %s", err)
+			}
+
+			statements, err := NewParser(tokens).Parse()
+			if err != nil {
+				t.Fatalf("while parsing: %s", err)
+			}
+
+			if len(statements) != 1 {
+				t.Errorf("expected 1 statement, got %d", len(statements))
+			}
+		})
+	}
+}
+
+func TestConstDocComments(t *testing.T) {
+	input := `
+/// The answer.
+const answer = 42;
+`
+
+	s, err := scanner.New(strings.NewReader(input), scanner.KeepDocComments())
+	if err != nil {
+		t.Fatalf("failed to initialize scanner: %s", err)
+	}
+
+	tokens, err := s.Scan()
+	if err != nil {
+		t.Fatalf("while scanning input: %s", err)
+	}
+
+	statements, err := NewParser(tokens).Parse()
+	if err != nil {
+		t.Fatalf("while parsing: %s", err)
+	}
+
+	if doc := statements[0].(*ast.VarStatement).Doc; doc != "The answer." {
+		t.Errorf("unexpected const doc: %q", doc)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/ggilmore/bradfield-languages/glox/token"
 )
@@ -17,9 +19,28 @@ type Scanner struct {
 	start   int
 	current int
 	line    int
+
+	keepDocComments bool
+
+	// interpolations tracks the "${...}" expressions that are currently
+	// being scanned, innermost last. Each entry counts the unmatched '{'
+	// seen inside of that expression, so that the scanner knows which '}'
+	// closes it.
+	interpolations []int
+}
+
+type Option func(s *Scanner)
+
+// KeepDocComments makes the scanner emit "///" comments as
+// token.KindDocComment tokens, instead of discarding them like other
+// comments.
+func KeepDocComments() Option {
+	return func(s *Scanner) {
+		s.keepDocComments = true
+	}
 }
 
-func New(r io.Reader) (*Scanner, error) {
+func New(r io.Reader, options ...Option) (*Scanner, error) {
 	b, err := io.ReadAll(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read from reader: %s", err)
@@ -27,9 +48,15 @@ func New(r io.Reader) (*Scanner, error) {
 
 	text := string(b)
 
-	return &Scanner{
+	s := &Scanner{
 		input: []rune(text),
-	}, nil
+	}
+
+	for _, o := range options {
+		o(s)
+	}
+
+	return s, nil
 }
 
 func (s *Scanner) Scan() ([]token.Token, error) {
@@ -38,6 +65,10 @@ func (s *Scanner) Scan() ([]token.Token, error) {
 		s.scanToken()
 	}
 
+	if len(s.interpolations) > 0 {
+		s.errs.Add(s.line, "Unterminated string interpolation.")
+	}
+
 	eof := token.Token{
 		Kind:    token.KindEOF,
 		Lexeme:  "",
@@ -60,28 +91,104 @@ func (s *Scanner) scanToken() {
 		s.addToken(token.KindRightParen)
 
 	case '{':
+		if len(s.interpolations) > 0 {
+			s.interpolations[len(s.interpolations)-1]++
+		}
+
 		s.addToken(token.KindLeftBrace)
 
 	case '}':
+		if len(s.interpolations) > 0 {
+			last := len(s.interpolations) - 1
+			if s.interpolations[last] == 0 {
+				// this closes the interpolated expression, so
+				// pick up scanning the rest of the string
+				s.interpolations = s.interpolations[:last]
+				s.string()
+				return
+			}
+
+			s.interpolations[last]--
+		}
+
 		s.addToken(token.KindRightBrace)
 
+	case '[':
+		s.addToken(token.KindLeftBracket)
+
+	case ']':
+		s.addToken(token.KindRightBracket)
+
 	case ',':
 		s.addToken(token.KindComma)
 
+	case ':':
+		s.addToken(token.KindColon)
+
+	case '?':
+		kind := token.KindQuestion
+		if s.match('?') {
+			kind = token.KindQuestionQuestion
+		}
+
+		s.addToken(kind)
+
 	case '.':
-		s.addToken(token.KindDot)
+		kind := token.KindDot
+		if s.match('.') {
+			kind = token.KindDotDot
+			if s.match('=') {
+				kind = token.KindDotDotEqual
+			} else if s.match('.') {
+				kind = token.KindDotDotDot
+			}
+		}
+
+		s.addToken(kind)
 
 	case '-':
-		s.addToken(token.KindMinus)
+		kind := token.KindMinus
+		if s.match('-') {
+			kind = token.KindMinusMinus
+		} else if s.match('=') {
+			kind = token.KindMinusEqual
+		}
+
+		s.addToken(kind)
 
 	case '+':
-		s.addToken(token.KindPlus)
+		kind := token.KindPlus
+		if s.match('+') {
+			kind = token.KindPlusPlus
+		} else if s.match('=') {
+			kind = token.KindPlusEqual
+		}
+
+		s.addToken(kind)
 
 	case ';':
 		s.addToken(token.KindSemicolon)
 
 	case '*':
-		s.addToken(token.KindStar)
+		kind := token.KindStar
+		if s.match('*') {
+			kind = token.KindStarStar
+		} else if s.match('=') {
+			kind = token.KindStarEqual
+		}
+
+		s.addToken(kind)
+
+	case '%':
+		s.addToken(token.KindPercent)
+
+	case '~':
+		// "~/" is floor division, since "//" already starts a comment
+		if s.match('/') {
+			s.addToken(token.KindTildeSlash)
+		} else {
+			s.errs.Add(s.line, fmt.Sprintf("Unexpected character %q.", c))
+		}
 
 	case '!':
 		kind := token.KindBang
@@ -95,6 +202,8 @@ func (s *Scanner) scanToken() {
 		kind := token.KindEqual
 		if s.match('=') {
 			kind = token.KindEqualEqual
+		} else if s.match('>') {
+			kind = token.KindFatArrow
 		}
 
 		s.addToken(kind)
@@ -117,9 +226,11 @@ func (s *Scanner) scanToken() {
 
 	case '/':
 		if s.match('/') {
-			for s.peek() != '\n' && !s.isAtEnd() {
-				s.advance()
-			}
+			s.lineComment()
+		} else if s.match('*') {
+			s.blockComment()
+		} else if s.match('=') {
+			s.addToken(token.KindSlashEqual)
 		} else {
 			s.addToken(token.KindSlash)
 		}
@@ -133,6 +244,9 @@ func (s *Scanner) scanToken() {
 	case '"':
 		s.string()
 
+	case '`':
+		s.rawString()
+
 	default:
 		if s.isDigit(c) {
 			s.number()
@@ -144,6 +258,46 @@ func (s *Scanner) scanToken() {
 	}
 }
 
+// lineComment skips the rest of a "//" comment, starting just after the
+// second '/'. Doc comments ("///", but not "////") are kept as tokens if the
+// scanner was asked to keep them.
+func (s *Scanner) lineComment() {
+	isDoc := s.keepDocComments && s.peek() == '/' && s.peekNext() != '/'
+
+	for s.peek() != '\n' && !s.isAtEnd() {
+		s.advance()
+	}
+
+	if isDoc {
+		text := string(s.input[s.start+3 : s.current])
+		s.addTokenLiteral(token.KindDocComment, strings.TrimPrefix(text, " "))
+	}
+}
+
+// blockComment skips a "/* ... */" comment, starting just after the
+// opening "/*". Block comments can be nested.
+func (s *Scanner) blockComment() {
+	startLine := s.line
+	depth := 1
+
+	for depth > 0 {
+		if s.isAtEnd() {
+			s.errs.Add(startLine, "Unterminated comment.")
+			return
+		}
+
+		c := s.advance()
+		switch {
+		case c == '\n':
+			s.line++
+		case c == '/' && s.match('*'):
+			depth++
+		case c == '*' && s.match('/'):
+			depth--
+		}
+	}
+}
+
 func (s *Scanner) identifier() {
 	for s.isAlphaNumeric(s.peek()) {
 		s.advance()
@@ -158,8 +312,117 @@ func (s *Scanner) identifier() {
 	s.addToken(kind)
 }
 
+// string scans the rest of a string literal, starting either just after
+// its opening '"' or just after the '}' that closes an interpolated
+// expression inside of it. Escape sequences are decoded into the token's
+// literal value. If a "${" is found, the string so far is emitted as
+// a KindInterpolation token and the scanner goes back to scanning
+// regular tokens until the matching '}'.
 func (s *Scanner) string() {
+	var value strings.Builder
+
 	for s.peek() != '"' && !s.isAtEnd() {
+		c := s.advance()
+
+		switch c {
+		case '\n':
+			s.line++
+			value.WriteRune(c)
+
+		case '\\':
+			s.escape(&value)
+
+		case '$':
+			if s.match('{') {
+				s.interpolations = append(s.interpolations, 0)
+				s.addTokenLiteral(token.KindInterpolation, value.String())
+				return
+			}
+
+			value.WriteRune(c)
+
+		default:
+			value.WriteRune(c)
+		}
+	}
+
+	if s.isAtEnd() {
+		s.errs.Add(s.line, "Unterminated string.")
+		return
+	}
+
+	// consume the closing '"'
+	s.advance()
+
+	s.addTokenLiteral(token.KindString, value.String())
+}
+
+// escape decodes the escape sequence that follows a '\\' inside of a string.
+func (s *Scanner) escape(value *strings.Builder) {
+	if s.isAtEnd() {
+		return
+	}
+
+	c := s.advance()
+	switch c {
+	case 'n':
+		value.WriteRune('\n')
+	case 't':
+		value.WriteRune('\t')
+	case 'r':
+		value.WriteRune('\r')
+	case '0':
+		value.WriteRune('\x00')
+	case '\\', '"', '$', '`':
+		value.WriteRune(c)
+	case 'u':
+		s.unicodeEscape(value)
+	default:
+		s.errs.Add(s.line, fmt.Sprintf("Invalid escape sequence '\\%c'.", c))
+
+		if c == '\n' {
+			s.line++
+		}
+	}
+}
+
+// unicodeEscape decodes a "\\u{XXXX}" escape sequence, starting just
+// after the 'u'.
+func (s *Scanner) unicodeEscape(value *strings.Builder) {
+	if !s.match('{') {
+		s.errs.Add(s.line, "Expect '{' after '\\u'.")
+		return
+	}
+
+	start := s.current
+	for s.isHexDigit(s.peek()) {
+		s.advance()
+	}
+
+	digits := string(s.input[start:s.current])
+	if !s.match('}') {
+		s.errs.Add(s.line, "Expect '}' after unicode escape sequence.")
+		return
+	}
+
+	if len(digits) == 0 || len(digits) > 6 {
+		s.errs.Add(s.line, fmt.Sprintf("Invalid unicode escape sequence '\\u{%s}'.", digits))
+		return
+	}
+
+	code, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil || !utf8.ValidRune(rune(code)) {
+		s.errs.Add(s.line, fmt.Sprintf("Invalid unicode code point '\\u{%s}'.", digits))
+		return
+	}
+
+	value.WriteRune(rune(code))
+}
+
+// rawString scans a string delimited by backticks. Raw strings can span
+// multiple lines, and don't support escape sequences or interpolation.
+func (s *Scanner) rawString() {
+	for s.peek() != '`' && !s.isAtEnd() {
 		if s.peek() == '\n' {
 			s.line++
 		}
@@ -168,11 +431,11 @@ func (s *Scanner) string() {
 	}
 
 	if s.isAtEnd() {
-		s.errs.Add(s.line, "Unterminated string.")
+		s.errs.Add(s.line, "Unterminated raw string.")
 		return
 	}
 
-	// consume the closing '"'
+	// consume the closing '`'
 	s.advance()
 
 	v := string(s.input[s.start+1 : s.current-1])
@@ -218,6 +481,12 @@ func (s *Scanner) isDigit(c rune) bool {
 	return c >= '0' && c <= '9'
 }
 
+func (s *Scanner) isHexDigit(c rune) bool {
+	return s.isDigit(c) ||
+		(c >= 'a' && c <= 'f') ||
+		(c >= 'A' && c <= 'F')
+}
+
 func (s *Scanner) match(expected rune) bool {
 	if s.isAtEnd() {
 		return false
@@ -1,52 +1,149 @@
 package scanner
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/ggilmore/bradfield-languages/glox/token"
 )
 
 func TestScanner(t *testing.T) {
 	for _, tt := range []struct {
 		name     string
 		input    string
-		expected []Token
+		expected []token.Token
 	}{
 		{
 			name:  "given",
 			input: "1 - (2 + 3)",
-			expected: []Token{
-				{Kind: KindNumber, Lexeme: "1", Literal: 1},
-				{Kind: KindMinus, Lexeme: "-"},
-				{Kind: KindLeftParen, Lexeme: "("},
-				{Kind: KindNumber, Lexeme: "2", Literal: 2},
-				{Kind: KindPlus, Lexeme: "+"},
-				{Kind: KindNumber, Lexeme: "3", Literal: 3},
-				{Kind: KindRightParen, Lexeme: ")"},
-				{Kind: KindEOF},
+			expected: []token.Token{
+				{Kind: token.KindNumber, Lexeme: "1", Literal: 1.0},
+				{Kind: token.KindMinus, Lexeme: "-"},
+				{Kind: token.KindLeftParen, Lexeme: "("},
+				{Kind: token.KindNumber, Lexeme: "2", Literal: 2.0},
+				{Kind: token.KindPlus, Lexeme: "+"},
+				{Kind: token.KindNumber, Lexeme: "3", Literal: 3.0},
+				{Kind: token.KindRightParen, Lexeme: ")"},
+				{Kind: token.KindEOF},
 			},
 		},
 		{
 			name:  "tricky multiplication",
 			input: "5*-20",
-			expected: []Token{
-				{Kind: KindNumber, Lexeme: "5", Literal: 5},
-				{Kind: KindStar, Lexeme: "*"},
-				{Kind: KindMinus, Lexeme: "-"},
-				{Kind: KindNumber, Lexeme: "20", Literal: 20},
-				{Kind: KindEOF},
+			expected: []token.Token{
+				{Kind: token.KindNumber, Lexeme: "5", Literal: 5.0},
+				{Kind: token.KindStar, Lexeme: "*"},
+				{Kind: token.KindMinus, Lexeme: "-"},
+				{Kind: token.KindNumber, Lexeme: "20", Literal: 20.0},
+				{Kind: token.KindEOF},
+			},
+		},
+		{
+			name:  "arithmetic operators",
+			input: "2**3 % 4 ~/ 5 * 6 // 7",
+			expected: []token.Token{
+				{Kind: token.KindNumber, Lexeme: "2", Literal: 2.0},
+				{Kind: token.KindStarStar, Lexeme: "**"},
+				{Kind: token.KindNumber, Lexeme: "3", Literal: 3.0},
+				{Kind: token.KindPercent, Lexeme: "%"},
+				{Kind: token.KindNumber, Lexeme: "4", Literal: 4.0},
+				{Kind: token.KindTildeSlash, Lexeme: "~/"},
+				{Kind: token.KindNumber, Lexeme: "5", Literal: 5.0},
+				{Kind: token.KindStar, Lexeme: "*"},
+				{Kind: token.KindNumber, Lexeme: "6", Literal: 6.0},
+				{Kind: token.KindEOF},
+			},
+		},
+		{
+			name:  "assignment operators",
+			input: "+= -= *= /= ++ -- **",
+			expected: []token.Token{
+				{Kind: token.KindPlusEqual, Lexeme: "+="},
+				{Kind: token.KindMinusEqual, Lexeme: "-="},
+				{Kind: token.KindStarEqual, Lexeme: "*="},
+				{Kind: token.KindSlashEqual, Lexeme: "/="},
+				{Kind: token.KindPlusPlus, Lexeme: "++"},
+				{Kind: token.KindMinusMinus, Lexeme: "--"},
+				{Kind: token.KindStarStar, Lexeme: "**"},
+				{Kind: token.KindEOF},
+			},
+		},
+		{
+			name:  "escape sequences",
+			input: `"a\tb\n\"c\"\\\$\u{2603}"`,
+			expected: []token.Token{
+				{Kind: token.KindString, Lexeme: `"a\tb\n\"c\"\\\$\u{2603}"`, Literal: "a\tb\n\"c\"\\$\u2603"},
+				{Kind: token.KindEOF},
+			},
+		},
+		{
+			name:  "raw string",
+			input: "`a\\n\n${b}`",
+			expected: []token.Token{
+				{Kind: token.KindString, Lexeme: "`a\\n\n${b}`", Literal: "a\\n\n${b}", Line: 1},
+				{Kind: token.KindEOF, Line: 1},
+			},
+		},
+		{
+			name:  "interpolation",
+			input: `"a ${b + "${c}"} d"`,
+			expected: []token.Token{
+				{Kind: token.KindInterpolation, Lexeme: `"a ${`, Literal: "a "},
+				{Kind: token.KindIdentifier, Lexeme: "b"},
+				{Kind: token.KindPlus, Lexeme: "+"},
+				{Kind: token.KindInterpolation, Lexeme: `"${`, Literal: ""},
+				{Kind: token.KindIdentifier, Lexeme: "c"},
+				{Kind: token.KindString, Lexeme: `}"`, Literal: ""},
+				{Kind: token.KindString, Lexeme: `} d"`, Literal: " d"},
+				{Kind: token.KindEOF},
+			},
+		},
+		{
+			name:  "braces inside interpolation",
+			input: `"${ {} }"`,
+			expected: []token.Token{
+				{Kind: token.KindInterpolation, Lexeme: `"${`, Literal: ""},
+				{Kind: token.KindLeftBrace, Lexeme: "{"},
+				{Kind: token.KindRightBrace, Lexeme: "}"},
+				{Kind: token.KindString, Lexeme: `}"`, Literal: ""},
+				{Kind: token.KindEOF},
+			},
+		},
+		{
+			name:  "dots and arrows",
+			input: "0..10 1..=2 => ...",
+			expected: []token.Token{
+				{Kind: token.KindNumber, Lexeme: "0", Literal: 0.0},
+				{Kind: token.KindDotDot, Lexeme: ".."},
+				{Kind: token.KindNumber, Lexeme: "10", Literal: 10.0},
+				{Kind: token.KindNumber, Lexeme: "1", Literal: 1.0},
+				{Kind: token.KindDotDotEqual, Lexeme: "..="},
+				{Kind: token.KindNumber, Lexeme: "2", Literal: 2.0},
+				{Kind: token.KindFatArrow, Lexeme: "=>"},
+				{Kind: token.KindDotDotDot, Lexeme: "..."},
+				{Kind: token.KindEOF},
+			},
+		},
+		{
+			name:  "nested block comments",
+			input: "1 /* a /* b */\n c */ 2 // d\n/// e",
+			expected: []token.Token{
+				{Kind: token.KindNumber, Lexeme: "1", Literal: 1.0},
+				{Kind: token.KindNumber, Lexeme: "2", Literal: 2.0, Line: 1},
+				{Kind: token.KindEOF, Line: 2},
 			},
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			s, err := NewScanner(strings.NewReader(tt.input))
+			s, err := New(strings.NewReader(tt.input))
 			if err != nil {
 				t.Fatalf("failed to initialize scanner: %s", err)
 			}
 
-			actual, err := s.ScanTokens()
+			actual, err := s.Scan()
 			if err != nil {
 				t.Errorf("while scanning input: %s", err)
 			}
@@ -56,10 +153,81 @@ func TestScanner(t *testing.T) {
 	}
 }
 
-func assertTokensEqual(t *testing.T, expected, actual []Token) {
+func TestScannerDocComments(t *testing.T) {
+	input := "/// Adds things.\n///   Indented.\n//// not a doc comment\nfun"
+	expected := []token.Token{
+		{Kind: token.KindDocComment, Lexeme: "/// Adds things.", Literal: "Adds things."},
+		{Kind: token.KindDocComment, Lexeme: "///   Indented.", Literal: "  Indented.", Line: 1},
+		{Kind: token.KindFun, Lexeme: "fun", Line: 3},
+		{Kind: token.KindEOF, Line: 3},
+	}
+
+	s, err := New(strings.NewReader(input), KeepDocComments())
+	if err != nil {
+		t.Fatalf("failed to initialize scanner: %s", err)
+	}
+
+	actual, err := s.Scan()
+	if err != nil {
+		t.Errorf("while scanning input: %s", err)
+	}
+
+	assertTokensEqual(t, expected, actual)
+}
+
+func TestScannerErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		input    string
+		expected []error
+	}{
+		{
+			name:     "bad escape is reported on its own line",
+			input:    "\"first\nsecond \\q\"",
+			expected: []error{&Error{Line: 1, Message: `Invalid escape sequence '\q'.`}},
+		},
+		{
+			name:     "bad unicode escape",
+			input:    `"\u{110000}"`,
+			expected: []error{&Error{Line: 0, Message: `Invalid unicode code point '\u{110000}'.`}},
+		},
+		{
+			name:     "unterminated block comment is reported where it starts",
+			input:    "1\n/* /* */\n",
+			expected: []error{&Error{Line: 1, Message: "Unterminated comment."}},
+		},
+		{
+			name:  "unterminated interpolation",
+			input: `"${a`,
+			expected: []error{
+				&Error{Line: 0, Message: "Unterminated string interpolation."},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("failed to initialize scanner: %s", err)
+			}
+
+			_, err = s.Scan()
+
+			var actual ErrorList
+			if !errors.As(err, &actual) {
+				t.Fatalf("expected an ErrorList, got: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.expected, []error(actual)); diff != "" {
+				t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+			}
+		})
+	}
+}
+
+func assertTokensEqual(t *testing.T, expected, actual []token.Token) {
 	t.Helper()
 
-	if diff := cmp.Diff(expected, actual, cmpopts.IgnoreFields(Token{}, "PosStart", "PosEnd")); diff != "" {
+	if diff := cmp.Diff(expected, actual); diff != "" {
 		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
 	}
 }
@@ -0,0 +1,174 @@
+// Package system provides natives that let glox scripts interact with
+// the file system and the process that they're running in. They're kept
+// out of the interpreter package so that embedders can choose not to
+// give scripts these capabilities.
+package system
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+	"github.com/ggilmore/bradfield-languages/glox/interpreter"
+)
+
+// Option defines the system natives, and the "args" global holding
+// "args", in an interpreter. readLine reads from "stdin".
+func Option(args []string, stdin io.Reader) interpreter.Option {
+	return func(i *interpreter.Interpreter) {
+		interpreter.Natives(Natives(stdin)...)(i)
+
+		list := &interpreter.LoxList{}
+		for _, a := range args {
+			list.Elements = append(list.Elements, a)
+		}
+
+		interpreter.Global("args", list)(i)
+	}
+}
+
+// Natives returns the system natives. readLine reads from "stdin".
+func Natives(stdin io.Reader) []*interpreter.Native {
+	lines := bufio.NewReader(stdin)
+
+	return []*interpreter.Native{
+		{Name: "readFile", Params: []interpreter.Type{interpreter.TypeString}, Fn: readFile},
+		{Name: "writeFile", Params: []interpreter.Type{interpreter.TypeString, interpreter.TypeString}, Fn: writeFile},
+		{Name: "readLines", Params: []interpreter.Type{interpreter.TypeString}, Fn: readLines},
+		{Name: "writeLines", Params: []interpreter.Type{interpreter.TypeString, interpreter.TypeList}, Fn: writeLines},
+		{Name: "listDir", Params: []interpreter.Type{interpreter.TypeString}, Fn: listDir},
+		{Name: "readLine", Fn: func(_ *interpreter.Interpreter, _ []*ast.Literal) (interface{}, error) {
+			return readLine(lines)
+		}},
+		{Name: "env", Params: []interpreter.Type{interpreter.TypeString}, Fn: env},
+		{Name: "exit", Params: []interpreter.Type{interpreter.TypeNumber}, Fn: exit},
+	}
+}
+
+// ExitError is returned by the exit native. It stops the script, and
+// asks whatever's running it to exit with Code.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+func (e *ExitError) IsHalt() {}
+
+var _ interpreter.Halt = &ExitError{}
+
+func readFile(_ *interpreter.Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	contents, err := os.ReadFile(arguments[0].Value.(string))
+	if err != nil {
+		return nil, err
+	}
+
+	return string(contents), nil
+}
+
+// writeFile replaces the file's contents with the string, creating the
+// file if it doesn't exist.
+func writeFile(_ *interpreter.Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	return nil, os.WriteFile(arguments[0].Value.(string), []byte(arguments[1].Value.(string)), 0644)
+}
+
+// readLines returns the lines of the file, without their line endings.
+func readLines(_ *interpreter.Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	f, err := os.Open(arguments[0].Value.(string))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	lines := &interpreter.LoxList{}
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		lines.Elements = append(lines.Elements, s.Text())
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// writeLines replaces the file's contents with the list's elements, each
+// followed by a newline. Elements that aren't strings are written the
+// same way that they'd be printed.
+func writeLines(_ *interpreter.Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	var b strings.Builder
+	for _, e := range arguments[1].Value.(*interpreter.LoxList).Elements {
+		b.WriteString((&ast.Literal{Value: e}).Output())
+		b.WriteString("\n")
+	}
+
+	return nil, os.WriteFile(arguments[0].Value.(string), []byte(b.String()), 0644)
+}
+
+// listDir returns the names of the directory's entries in sorted order.
+func listDir(_ *interpreter.Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	entries, err := os.ReadDir(arguments[0].Value.(string))
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+
+	sort.Strings(names)
+
+	list := &interpreter.LoxList{}
+	for _, n := range names {
+		list.Elements = append(list.Elements, n)
+	}
+
+	return list, nil
+}
+
+// readLine returns the next line from "r" without its line ending, or
+// nil if there aren't any lines left.
+func readLine(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if errors.Is(err, io.EOF) {
+		if line == "" {
+			return nil, nil
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r"), nil
+}
+
+// env returns the value of an environment variable, or nil if it isn't
+// set.
+func env(_ *interpreter.Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	value, found := os.LookupEnv(arguments[0].Value.(string))
+	if !found {
+		return nil, nil
+	}
+
+	return value, nil
+}
+
+func exit(_ *interpreter.Interpreter, arguments []*ast.Literal) (interface{}, error) {
+	code := arguments[0].Value.(float64)
+	if code != math.Trunc(code) || code < 0 || code > 255 {
+		return nil, fmt.Errorf("status must be an integer from 0 to 255, got %s.", (&ast.Literal{Value: code}).Output())
+	}
+
+	return nil, &ExitError{Code: int(code)}
+}
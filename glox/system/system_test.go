@@ -0,0 +1,91 @@
+package system
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ggilmore/bradfield-languages/glox/ast"
+	"github.com/ggilmore/bradfield-languages/glox/interpreter"
+)
+
+func TestFiles(t *testing.T) {
+	natives := byName(Natives(strings.NewReader("")))
+	path := filepath.Join(t.TempDir(), "lines.txt")
+
+	lines := &interpreter.LoxList{Elements: []interface{}{"a", 2.0, nil}}
+	_, err := call(natives["writeLines"], path, lines)
+	if err != nil {
+		t.Fatalf("writeLines: %s", err)
+	}
+
+	contents, err := call(natives["readFile"], path)
+	if err != nil {
+		t.Fatalf("readFile: %s", err)
+	}
+
+	if contents != "a\n2\nnil\n" {
+		t.Errorf("unexpected file contents: %q", contents)
+	}
+
+	actual, err := call(natives["readLines"], path)
+	if err != nil {
+		t.Fatalf("readLines: %s", err)
+	}
+
+	expected := &interpreter.LoxList{Elements: []interface{}{"a", "2", "nil"}}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestReadLine(t *testing.T) {
+	natives := byName(Natives(strings.NewReader("first\r\nsecond\nlast")))
+
+	var actual []interface{}
+	for i := 0; i < 4; i++ {
+		line, err := call(natives["readLine"])
+		if err != nil {
+			t.Fatalf("readLine: %s", err)
+		}
+
+		actual = append(actual, line)
+	}
+
+	expected := []interface{}{"first", "second", "last", nil}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("non-zero diff (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestExit(t *testing.T) {
+	natives := byName(Natives(strings.NewReader("")))
+
+	_, err := call(natives["exit"], 3.0)
+
+	var exit *ExitError
+	if !errors.As(err, &exit) || exit.Code != 3 {
+		t.Errorf("expected an ExitError with code 3, got: %v", err)
+	}
+}
+
+func byName(natives []*interpreter.Native) map[string]*interpreter.Native {
+	named := make(map[string]*interpreter.Native)
+	for _, n := range natives {
+		named[n.Name] = n
+	}
+
+	return named
+}
+
+func call(native *interpreter.Native, arguments ...interface{}) (interface{}, error) {
+	var literals []*ast.Literal
+	for _, a := range arguments {
+		literals = append(literals, &ast.Literal{Value: a})
+	}
+
+	return native.Fn(nil, literals)
+}
@@ -9,15 +9,33 @@ const (
 	KindRightParen
 	KindLeftBrace
 	KindRightBrace
+	KindLeftBracket
+	KindRightBracket
 	KindComma
+	KindColon
+	KindQuestion
+	KindQuestionQuestion
 	KindDot
+	KindDotDot
+	KindDotDotEqual
+	KindDotDotDot
 	KindMinus
+	KindMinusMinus
+	KindMinusEqual
 	KindPlus
+	KindPlusPlus
+	KindPlusEqual
 	KindSemicolon
 	KindSlash
+	KindSlashEqual
 	KindStar
+	KindStarEqual
+	KindStarStar
+	KindPercent
+	KindTildeSlash
 
 	KindEqual
+	KindFatArrow
 	KindBang
 	KindBangEqual
 	KindEqualEqual
@@ -28,6 +46,9 @@ const (
 
 	KindIdentifier
 	KindString
+	// KindInterpolation is the part of a string literal that comes
+	// before an interpolated "${...}" expression
+	KindInterpolation
 	KindNumber
 
 	KindLet
@@ -48,8 +69,23 @@ const (
 	KindTrue
 	KindVar
 	KindWhile
+	KindBreak
+	KindContinue
+	KindMatch
+	KindCase
+	KindThrow
+	KindTry
+	KindCatch
+	KindFinally
+	KindImport
+	KindAs
+	KindConst
 	KindDebug
 
+	// KindDocComment is a "///" comment. The scanner only emits these
+	// when it's asked to keep doc comments.
+	KindDocComment
+
 	KindEOF
 )
 
@@ -63,23 +99,59 @@ func (t Kind) String() string {
 		return "LeftBrace"
 	case KindRightBrace:
 		return "RightBrace"
+	case KindLeftBracket:
+		return "LeftBracket"
+	case KindRightBracket:
+		return "RightBracket"
 	case KindComma:
 		return "Comma"
+	case KindColon:
+		return "Colon"
+	case KindQuestion:
+		return "Question"
+	case KindQuestionQuestion:
+		return "QuestionQuestion"
 	case KindDot:
 		return "Dot"
+	case KindDotDot:
+		return "DotDot"
+	case KindDotDotEqual:
+		return "DotDotEqual"
+	case KindDotDotDot:
+		return "DotDotDot"
 	case KindMinus:
 		return "Minus"
+	case KindMinusMinus:
+		return "MinusMinus"
+	case KindMinusEqual:
+		return "MinusEqual"
 	case KindPlus:
 		return "Plus"
+	case KindPlusPlus:
+		return "PlusPlus"
+	case KindPlusEqual:
+		return "PlusEqual"
 	case KindSemicolon:
 		return "Semicolon"
 	case KindSlash:
 		return "Slash"
+	case KindSlashEqual:
+		return "SlashEqual"
 	case KindStar:
 		return "Star"
+	case KindStarEqual:
+		return "StarEqual"
+	case KindStarStar:
+		return "StarStar"
+	case KindPercent:
+		return "Percent"
+	case KindTildeSlash:
+		return "TildeSlash"
 
 	case KindEqual:
 		return "Equal"
+	case KindFatArrow:
+		return "FatArrow"
 	case KindBang:
 		return "Bang"
 	case KindBangEqual:
@@ -99,6 +171,8 @@ func (t Kind) String() string {
 		return "Identifier"
 	case KindString:
 		return "String"
+	case KindInterpolation:
+		return "Interpolation"
 	case KindNumber:
 		return "Number"
 
@@ -136,11 +210,36 @@ func (t Kind) String() string {
 		return "Var"
 	case KindWhile:
 		return "While"
+	case KindBreak:
+		return "Break"
+	case KindContinue:
+		return "Continue"
+	case KindMatch:
+		return "Match"
+	case KindCase:
+		return "Case"
+	case KindThrow:
+		return "Throw"
+	case KindTry:
+		return "Try"
+	case KindCatch:
+		return "Catch"
+	case KindFinally:
+		return "Finally"
+	case KindImport:
+		return "Import"
+	case KindAs:
+		return "As"
+	case KindConst:
+		return "Const"
 	case KindIn:
 		return "In"
 	case KindDebug:
 		return "Debug"
 
+	case KindDocComment:
+		return "DocComment"
+
 	case KindEOF:
 		return "EOF"
 	}
@@ -150,25 +249,36 @@ func (t Kind) String() string {
 }
 
 var Keywords = map[string]Kind{
-	"and":    KindAnd,
-	"let":    KindLet,
-	"in":     KindIn,
-	"class":  KindClass,
-	"else":   KindElse,
-	"false":  KindFalse,
-	"for":    KindFor,
-	"fun":    KindFun,
-	"if":     KindIf,
-	"nil":    KindNil,
-	"or":     KindOr,
-	"print":  KindPrint,
-	"return": KindReturn,
-	"super":  KindSuper,
-	"this":   KindThis,
-	"true":   KindTrue,
-	"var":    KindVar,
-	"while":  KindWhile,
-	"debug":  KindDebug,
+	"and":      KindAnd,
+	"let":      KindLet,
+	"in":       KindIn,
+	"class":    KindClass,
+	"else":     KindElse,
+	"false":    KindFalse,
+	"for":      KindFor,
+	"fun":      KindFun,
+	"if":       KindIf,
+	"nil":      KindNil,
+	"or":       KindOr,
+	"print":    KindPrint,
+	"return":   KindReturn,
+	"super":    KindSuper,
+	"this":     KindThis,
+	"true":     KindTrue,
+	"var":      KindVar,
+	"while":    KindWhile,
+	"break":    KindBreak,
+	"continue": KindContinue,
+	"match":    KindMatch,
+	"case":     KindCase,
+	"throw":    KindThrow,
+	"try":      KindTry,
+	"catch":    KindCatch,
+	"finally":  KindFinally,
+	"import":   KindImport,
+	"as":       KindAs,
+	"const":    KindConst,
+	"debug":    KindDebug,
 }
 
 type Token struct {